@@ -34,6 +34,18 @@ const (
 	// reconciler for that resource. This condition indicates the state of the
 	// reconciler.
 	ResourceGroupConditionTypeReconcilerReady ConditionType = "ReconcilerReady"
+	// ResourceGroupConditionTypeServiceAccountsVerified indicates whether the
+	// default service accounts of a ResourceGroup are allowed to manage the
+	// resources of the graph.
+	ResourceGroupConditionTypeServiceAccountsVerified ConditionType = "ServiceAccountsVerified"
+	// ResourceGroupConditionTypeInstancesCleanedUp indicates whether all the
+	// instances of a ResourceGroup being deleted are gone. The deletion of a
+	// ResourceGroup is held while instances remain.
+	ResourceGroupConditionTypeInstancesCleanedUp ConditionType = "InstancesCleanedUp"
+	// ResourceGroupConditionTypeKindClaimed indicates whether a ResourceGroup
+	// owns the kind it defines. A kind can only be claimed by a single
+	// ResourceGroup, the others are left in the Conflict state.
+	ResourceGroupConditionTypeKindClaimed ConditionType = "KindClaimed"
 )
 
 const (
@@ -47,6 +59,10 @@ const (
 
 	// Something is wrong but i'm gonna try again
 	InstanceConditionTypeError ConditionType = "Error"
+
+	// Reconciliation is paused through the paused annotation, on the instance
+	// or on its ResourceGroup
+	InstanceConditionTypePaused ConditionType = "Paused"
 )
 
 // Condition is the common struct used by all CRDs managed by ACK service
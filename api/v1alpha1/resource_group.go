@@ -43,6 +43,27 @@ type ResourceGroupSpec struct {
 	//
 	// +kubebuilder:validation:Optional
 	DefaultServiceAccounts map[string]string `json:"defaultServiceAccounts,omitempty"`
+	// AllowedServiceAccounts is the list of service accounts instances can
+	// request through the kro.run/service-account annotation. The requested
+	// service account is looked up in the namespace of the instance.
+	//
+	// +kubebuilder:validation:Optional
+	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
+	// ReconcileInterval is the interval at which active instances are
+	// reconciled again, even if they didn't change. Defaults to 5 minutes,
+	// zero disables the periodic reconciliation. Instances can override it
+	// through the kro.run/reconcile-interval annotation.
+	//
+	// +kubebuilder:validation:Optional
+	ReconcileInterval *metav1.Duration `json:"reconcileInterval,omitempty"`
+	// QueueWeight is the share of the controller workers the instances get
+	// when the instances of several resource groups are waiting to be
+	// reconciled. Up to QueueWeight instances are reconciled before moving
+	// to the instances of the next resource group. Defaults to 1.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	QueueWeight *int32 `json:"queueWeight,omitempty"`
 }
 
 // Schema represents the attributes that define an instance of
@@ -88,6 +109,59 @@ type Resource struct {
 	ReadyWhen []string `json:"readyWhen,omitempty"`
 	// +kubebuilder:validation:Optional
 	IncludeWhen []string `json:"includeWhen,omitempty"`
+	// DependsOn is a list of resource IDs this resource depends on, in
+	// addition to the ones inferred from its expressions. It is used to
+	// order resources that don't reference each other.
+	//
+	// +kubebuilder:validation:Optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// ReadinessPolicy defines how often the readiness of the resource is
+	// checked while it isn't ready, and how long it is waited for.
+	//
+	// +kubebuilder:validation:Optional
+	ReadinessPolicy *ReadinessPolicy `json:"readinessPolicy,omitempty"`
+	// FailWhen is a list of expressions marking the resource as failed as
+	// soon as one of them evaluates to true, e.g
+	// ${cluster.status.status == "FAILED"}. A failed resource isn't
+	// checked again until the spec of the instance changes.
+	//
+	// +kubebuilder:validation:Optional
+	FailWhen []string `json:"failWhen,omitempty"`
+	// ReadyTimeout is how long the resource is waited for, since its
+	// creation, before it is marked as failed. Unlike the timeout of the
+	// readiness policy, which only degrades the instance, a failed resource
+	// isn't checked again until the spec of the instance changes.
+	//
+	// +kubebuilder:validation:Optional
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+}
+
+// ReadinessPolicy defines how the readiness of a resource is polled. The
+// interval between two checks starts at InitialDelay, and grows by
+// BackoffFactor up to MaxInterval.
+type ReadinessPolicy struct {
+	// InitialDelay is the interval before the first readiness check of the
+	// resource. Defaults to 3 seconds.
+	//
+	// +kubebuilder:validation:Optional
+	InitialDelay *metav1.Duration `json:"initialDelay,omitempty"`
+	// MaxInterval is the maximum interval between two readiness checks.
+	// Defaults to 5 minutes.
+	//
+	// +kubebuilder:validation:Optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+	// BackoffFactor is the factor the interval between two readiness checks
+	// grows by. Defaults to 1, i.e a constant interval.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	BackoffFactor *int32 `json:"backoffFactor,omitempty"`
+	// Timeout is how long the resource is waited for, since its creation,
+	// before the instance is marked as degraded. The readiness of the
+	// resource is still checked after the timeout. No timeout by default.
+	//
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // ResourceGroupStatus defines the observed state of ResourceGroup
@@ -96,10 +170,28 @@ type ResourceGroupStatus struct {
 	State ResourceGroupState `json:"state,omitempty"`
 	// TopologicalOrder is the topological order of the resourcegroup graph
 	TopologicalOrder []string `json:"topologicalOrder,omitempty"`
+	// TopologicalLevels groups the resources of the resourcegroup graph in
+	// levels. Resources of a same level only depend on resources of earlier
+	// levels, and can be processed together.
+	TopologicalLevels [][]string `json:"topologicalLevels,omitempty"`
 	// Conditions represent the latest available observations of an object's state
 	Conditions []Condition `json:"conditions,omitempty"`
 	// Resources represents the resources, and their information (dependencies for now)
 	Resources []ResourceInformation `json:"resources,omitempty"`
+	// ValidationErrors lists the errors found while validating the resourcegroup
+	ValidationErrors []ValidationError `json:"validationErrors,omitempty"`
+}
+
+// ValidationError defines an error found while validating the resourcegroup
+type ValidationError struct {
+	// ResourceID is the id of the resource the error was found in
+	ResourceID string `json:"resourceID,omitempty"`
+	// Path is the path of the field the error was found in
+	Path string `json:"path,omitempty"`
+	// Expression is the CEL expression that failed validation
+	Expression string `json:"expression,omitempty"`
+	// Message is the description of the error
+	Message string `json:"message,omitempty"`
 }
 
 // ResourceInformation defines the information about a resource
@@ -112,7 +204,8 @@ type ResourceInformation struct {
 }
 
 // Dependency defines the dependency a resource has observed
-// from the resources it points to based on expressions
+// from the resources it points to based on expressions, or
+// declared explicitly with dependsOn
 type Dependency struct {
 	// ID represents the id of the dependency resource
 	ID string `json:"id,omitempty"`
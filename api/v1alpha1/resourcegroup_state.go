@@ -21,4 +21,7 @@ const (
 	ResourceGroupStateActive ResourceGroupState = "Active"
 	// ResourceGroupStateInactive represents the inactive state of the resource group
 	ResourceGroupStateInactive ResourceGroupState = "Inactive"
+	// ResourceGroupStateConflict represents a resource group whose kind is
+	// already claimed by another resource group.
+	ResourceGroupStateConflict ResourceGroupState = "Conflict"
 )
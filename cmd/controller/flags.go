@@ -0,0 +1,112 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"flag"
+
+	krocel "github.com/awslabs/kro/pkg/cel"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/tracing"
+)
+
+// options are the command line options of the controller.
+type options struct {
+	metricsAddr                              string
+	enableLeaderElection                     bool
+	probeAddr                                string
+	allowCRDDeletion                         bool
+	resourceGroupConcurrentReconciles        int
+	dynamicControllerConcurrentReconciles    int
+	dynamicControllerMaxConcurrentReconciles int
+	// reconciler parameters
+	resyncPeriod           int
+	queueMaxRetries        int
+	shutdownTimeout        int
+	logLevel               int
+	qps                    float64
+	burst                  int
+	impersonationCacheSize int
+	impersonationCacheTTL  int
+	// cel limits
+	celCostLimit         uint64
+	celEvaluationTimeout int
+	// instance filtering
+	watchNamespaces    string
+	watchLabelSelector string
+	// tracing
+	otlpEndpoint       string
+	otlpInsecure       bool
+	tracingSampleRatio float64
+	// admission webhooks
+	enableWebhooks bool
+	webhookPort    int
+	webhookCertDir string
+}
+
+// bindFlags registers the command line flags of the controller in the given
+// flag set.
+func (o *options) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.metricsAddr, "metrics-bind-address", ":8078", "The address the metric endpoint binds to.")
+	fs.StringVar(&o.probeAddr, "health-probe-bind-address", ":8079", "The address the probe endpoint binds to.")
+	fs.BoolVar(&o.enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	fs.BoolVar(&o.allowCRDDeletion, "allow-crd-deletion", false, "allow kro to delete CRDs")
+	fs.IntVar(&o.resourceGroupConcurrentReconciles, "resource-group-concurrent-reconciles", 1, "The number of resource group reconciles to run in parallel")
+	fs.IntVar(&o.dynamicControllerConcurrentReconciles, "dynamic-controller-concurrent-reconciles", 1, "The number of dynamic controller reconciles to run in parallel")
+	fs.IntVar(&o.dynamicControllerMaxConcurrentReconciles, "dynamic-controller-max-concurrent-reconciles", 0,
+		"The maximum number of dynamic controller reconciles to run in parallel. When greater than "+
+			"dynamic-controller-concurrent-reconciles, workers are scaled between the two based on the load")
+	// reconciler parameters
+	fs.IntVar(&o.resyncPeriod, "dynamic-controller-default-resync-period", 10,
+		"interval at which the controller will re list resources even with no changes, in hours")
+	fs.IntVar(&o.queueMaxRetries, "dynamic-controller-default-queue-max-retries", 20,
+		"maximum number of retries for an item in the queue will be retried before being dropped")
+	fs.IntVar(&o.shutdownTimeout, "dynamic-controller-default-shutdown-timeout", 60,
+		"maximum duration to wait for the controller to gracefully shutdown, in seconds")
+	// log level flags
+	fs.IntVar(&o.logLevel, "log-level", 10, "The log level verbosity. 0 is the least verbose, 5 is the most verbose.")
+	// qps and burst
+	fs.Float64Var(&o.qps, "client-qps", 100, "The number of queries per second to allow")
+	fs.IntVar(&o.burst, "client-burst", 150,
+		"The number of requests that can be stored for processing before the server starts enforcing the QPS limit")
+	// impersonation cache
+	fs.IntVar(&o.impersonationCacheSize, "impersonation-cache-size", kroclient.DefaultImpersonationCacheSize,
+		"maximum number of impersonated clients kept in the cache")
+	fs.IntVar(&o.impersonationCacheTTL, "impersonation-cache-ttl", int(kroclient.DefaultImpersonationCacheTTL.Seconds()),
+		"duration after which an impersonated client is evicted from the cache, in seconds")
+	// cel limits
+	fs.Uint64Var(&o.celCostLimit, "cel-cost-limit", krocel.DefaultCostLimit,
+		"maximum cost of a single CEL expression, resource groups with more expensive expressions are rejected")
+	fs.IntVar(&o.celEvaluationTimeout, "cel-evaluation-timeout", int(krocel.DefaultEvaluationTimeout.Milliseconds()),
+		"maximum duration of a single CEL expression evaluation, in milliseconds")
+	// instance filtering
+	fs.StringVar(&o.watchNamespaces, "watch-namespaces", "",
+		"comma separated list of namespaces the dynamic controller watches instances in, all namespaces are watched when empty")
+	fs.StringVar(&o.watchLabelSelector, "watch-label-selector", "",
+		"label selector restricting the instances watched by the dynamic controller, all instances are watched when empty")
+	// tracing
+	fs.StringVar(&o.otlpEndpoint, "otlp-endpoint", "",
+		"address of the OTLP gRPC collector traces are exported to, tracing is disabled when empty")
+	fs.BoolVar(&o.otlpInsecure, "otlp-insecure", false, "disable transport security when exporting traces")
+	fs.Float64Var(&o.tracingSampleRatio, "tracing-sample-ratio", tracing.DefaultSampleRatio,
+		"ratio of traces sampled, between 0 and 1")
+	// admission webhooks
+	fs.BoolVar(&o.enableWebhooks, "enable-webhooks", false,
+		"serve the validating admission webhooks rejecting invalid resource groups and instances")
+	fs.IntVar(&o.webhookPort, "webhook-port", 9443, "The port the admission webhook server listens on.")
+	fs.StringVar(&o.webhookCertDir, "webhook-cert-dir", "",
+		"directory containing the tls.crt and tls.key files of the webhook server, defaults to the controller-runtime default")
+}
@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chartArgs returns the container args of the controller deployment of the
+// Helm chart, with the environment variables they reference expanded.
+func chartArgs(t *testing.T, env map[string]string) []string {
+	data, err := os.ReadFile("../../helm/templates/deployment.yaml")
+	require.NoError(t, err)
+
+	var args []string
+	inArgs := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "args:" {
+			inArgs = true
+			continue
+		}
+		if !inArgs {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "- ") {
+			break
+		}
+		args = append(args, strings.Trim(strings.TrimPrefix(trimmed, "- "), `"`))
+	}
+	require.NotEmpty(t, args)
+
+	variable := regexp.MustCompile(`\$\((\w+)\)`)
+	for i, arg := range args {
+		args[i] = variable.ReplaceAllStringFunc(arg, func(ref string) string {
+			name := variable.FindStringSubmatch(ref)[1]
+			value, ok := env[name]
+			require.True(t, ok, "unexpected environment variable %s", name)
+			return value
+		})
+	}
+	return args
+}
+
+func TestChartArgs(t *testing.T) {
+	env := map[string]string{
+		"KRO_ALLOW_CRD_DELETION":                           "false",
+		"KRO_METRICS_BIND_ADDRESS":                         ":8078",
+		"KRO_HEALTH_PROBE_BIND_ADDRESS":                    ":8079",
+		"KRO_RESOURCE_GROUP_CONCURRENT_RECONCILES":         "1",
+		"KRO_DYNAMIC_CONTROLLER_CONCURRENT_RECONCILES":     "1",
+		"KRO_DYNAMIC_CONTROLLER_MAX_CONCURRENT_RECONCILES": "4",
+		"KRO_LOG_LEVEL":                                    "3",
+		"KRO_CEL_COST_LIMIT":                               "1000",
+		"KRO_CEL_EVALUATION_TIMEOUT":                       "100",
+		"KRO_IMPERSONATION_CACHE_SIZE":                     "100",
+		"KRO_IMPERSONATION_CACHE_TTL":                      "300",
+		"KRO_WATCH_NAMESPACES":                             "team-a,team-b",
+		"KRO_WATCH_LABEL_SELECTOR":                         "team=a",
+		"KRO_OTLP_ENDPOINT":                                "collector:4317",
+		"KRO_OTLP_INSECURE":                                "false",
+		"KRO_TRACING_SAMPLE_RATIO":                         "0.5",
+		"KRO_ENABLE_WEBHOOKS":                              "true",
+		"KRO_WEBHOOK_PORT":                                 "9443",
+		"KRO_WEBHOOK_CERT_DIR":                             "/etc/kro/webhook-certs",
+	}
+	args := chartArgs(t, env)
+
+	var o options
+	fs := flag.NewFlagSet("controller", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	o.bindFlags(fs)
+	require.NoError(t, fs.Parse(args))
+
+	// Bool flags given their value as a separate argument stop the parsing,
+	// leaving the remaining flags unparsed.
+	assert.Empty(t, fs.Args())
+
+	var set []string
+	fs.Visit(func(f *flag.Flag) { set = append(set, f.Name) })
+	var passed []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			passed = append(passed, strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0])
+		}
+	}
+	assert.ElementsMatch(t, passed, set)
+
+	assert.False(t, o.allowCRDDeletion)
+	assert.False(t, o.otlpInsecure)
+	assert.True(t, o.enableWebhooks)
+	assert.Equal(t, 4, o.dynamicControllerMaxConcurrentReconciles)
+	assert.Equal(t, "team-a,team-b", o.watchNamespaces)
+	assert.Equal(t, "/etc/kro/webhook-certs", o.webhookCertDir)
+}
@@ -17,9 +17,11 @@ import (
 	"context"
 	"flag"
 	"os"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -30,12 +32,16 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xv1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	krocel "github.com/awslabs/kro/pkg/cel"
 	kroclient "github.com/awslabs/kro/pkg/client"
 	resourcegroupctrl "github.com/awslabs/kro/pkg/controller/resourcegroup"
 	"github.com/awslabs/kro/pkg/dynamiccontroller"
 	"github.com/awslabs/kro/pkg/graph"
+	"github.com/awslabs/kro/pkg/tracing"
+	krowebhook "github.com/awslabs/kro/pkg/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -60,57 +66,39 @@ func (c customLevelEnabler) Enabled(lvl zapcore.Level) bool {
 }
 
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
-	var allowCRDDeletion bool
-	var resourceGroupConcurrentReconciles int
-	var dynamicControllerConcurrentReconciles int
-	// reconciler parameters
-	var resyncPeriod int
-	var queueMaxRetries int
-	var shutdownTimeout int
-	// var dynamicControllerDefaultResyncPeriod int
-	var logLevel int
-	var qps float64
-	var burst int
-
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8078", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8079", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	flag.BoolVar(&allowCRDDeletion, "allow-crd-deletion", false, "allow kro to delete CRDs")
-	flag.IntVar(&resourceGroupConcurrentReconciles, "resource-group-concurrent-reconciles", 1, "The number of resource group reconciles to run in parallel")
-	flag.IntVar(&dynamicControllerConcurrentReconciles, "dynamic-controller-concurrent-reconciles", 1, "The number of dynamic controller reconciles to run in parallel")
-	// reconciler parametes
-	flag.IntVar(&resyncPeriod, "dynamic-controller-default-resync-period", 10,
-		"interval at which the controller will re list resources even with no changes, in hours")
-	flag.IntVar(&queueMaxRetries, "dynamic-controller-default-queue-max-retries", 20,
-		"maximum number of retries for an item in the queue will be retried before being dropped")
-	flag.IntVar(&shutdownTimeout, "dynamic-controller-default-shutdown-timeout", 60,
-		"maximum duration to wait for the controller to gracefully shutdown, in seconds")
-	// log level flags
-	flag.IntVar(&logLevel, "log-level", 10, "The log level verbosity. 0 is the least verbose, 5 is the most verbose.")
-	// qps and burst
-	flag.Float64Var(&qps, "client-qps", 100, "The number of queries per second to allow")
-	flag.IntVar(&burst, "client-burst", 150,
-		"The number of requests that can be stored for processing before the server starts enforcing the QPS limit")
-
+	var o options
+	o.bindFlags(flag.CommandLine)
 	flag.Parse()
 
 	opts := zap.Options{
 		Development: true,
-		Level:       customLevelEnabler{level: logLevel},
+		Level:       customLevelEnabler{level: o.logLevel},
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
 	}
 	rootLogger := zap.New(zap.UseFlagOptions(&opts))
 
 	ctrl.SetLogger(rootLogger)
 
+	if _, err := labels.Parse(o.watchLabelSelector); err != nil {
+		setupLog.Error(err, "invalid watch label selector")
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    o.otlpEndpoint,
+		Insecure:    o.otlpInsecure,
+		SampleRatio: o.tracingSampleRatio,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
 	set, err := kroclient.NewSet(kroclient.Config{
-		QPS:   float32(qps),
-		Burst: burst,
+		QPS:                    float32(o.qps),
+		Burst:                  o.burst,
+		ImpersonationCacheSize: o.impersonationCacheSize,
+		ImpersonationCacheTTL:  time.Duration(o.impersonationCacheTTL) * time.Second,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to create client set")
@@ -121,10 +109,14 @@ func main() {
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+			BindAddress: o.metricsAddr,
 		},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    o.webhookPort,
+			CertDir: o.webhookCertDir,
+		}),
+		HealthProbeBindAddress: o.probeAddr,
+		LeaderElection:         o.enableLeaderElection,
 		LeaderElectionID:       "6f0f64a5.kro.run",
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
@@ -144,15 +136,22 @@ func main() {
 	}
 
 	dc := dynamiccontroller.NewDynamicController(rootLogger, dynamiccontroller.Config{
-		Workers: dynamicControllerConcurrentReconciles,
+		Workers:    o.dynamicControllerConcurrentReconciles,
+		MaxWorkers: o.dynamicControllerMaxConcurrentReconciles,
 		// TODO(a-hilaly): expose these as flags
-		ShutdownTimeout: time.Duration(shutdownTimeout) * time.Second,
-		ResyncPeriod:    time.Duration(resyncPeriod) * time.Hour,
-		QueueMaxRetries: queueMaxRetries,
+		ShutdownTimeout: time.Duration(o.shutdownTimeout) * time.Second,
+		ResyncPeriod:    time.Duration(o.resyncPeriod) * time.Hour,
+		QueueMaxRetries: o.queueMaxRetries,
+		Namespaces:      parseNamespaces(o.watchNamespaces),
+		LabelSelector:   o.watchLabelSelector,
 	}, set.Dynamic())
 
 	resourceGroupGraphBuilder, err := graph.NewBuilder(
 		restConfig,
+		krocel.Limits{
+			CostLimit:         o.celCostLimit,
+			EvaluationTimeout: time.Duration(o.celEvaluationTimeout) * time.Millisecond,
+		},
 	)
 	if err != nil {
 		setupLog.Error(err, "unable to create resource group graph builder")
@@ -163,19 +162,22 @@ func main() {
 		rootLogger,
 		mgr.GetClient(),
 		set,
-		allowCRDDeletion,
+		o.allowCRDDeletion,
 		dc,
 		resourceGroupGraphBuilder,
+		mgr.GetEventRecorderFor("kro"),
 	)
 	err = ctrl.NewControllerManagedBy(
 		mgr,
 	).For(
 		&xv1alpha1.ResourceGroup{},
 	).WithEventFilter(
-		predicate.GenerationChangedPredicate{},
+		// Annotations such as paused and allow-breaking-changes don't bump
+		// the generation of the ResourceGroup.
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
 	).WithOptions(
 		ctrlrtcontroller.Options{
-			MaxConcurrentReconciles: resourceGroupConcurrentReconciles,
+			MaxConcurrentReconciles: o.resourceGroupConcurrentReconciles,
 		},
 	).Complete(reconcile.AsReconciler[*xv1alpha1.ResourceGroup](mgr.GetClient(), reconciler))
 	if err != nil {
@@ -183,6 +185,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	if o.enableWebhooks {
+		validator := krowebhook.NewResourceGroupValidator(
+			resourceGroupGraphBuilder,
+			set.CRD(kroclient.CRDWrapperConfig{Log: rootLogger}),
+		)
+		if err := validator.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ResourceGroup")
+			os.Exit(1)
+		}
+		instanceValidator := krowebhook.NewInstanceValidator(mgr.GetClient(), resourceGroupGraphBuilder)
+		if err := instanceValidator.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Instance")
+			os.Exit(1)
+		}
+	}
+
 	go dc.Run(context.Background())
 
 	//+kubebuilder:scaffold:builder
@@ -206,4 +224,22 @@ func main() {
 
 	<-ctx.Done()
 
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+}
+
+// parseNamespaces parses a comma separated list of namespaces, ignoring
+// empty entries.
+func parseNamespaces(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
 }
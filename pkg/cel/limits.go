@@ -0,0 +1,157 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+const (
+	// DefaultCostLimit is the default maximum cost of a single expression. It
+	// matches the per expression limit the Kubernetes API server applies to
+	// CRD validation rules.
+	DefaultCostLimit uint64 = 1000000
+	// DefaultEvaluationTimeout is the default maximum duration of a single
+	// expression evaluation.
+	DefaultEvaluationTimeout = 1 * time.Second
+
+	// estimatedMaxSize is the size the cost estimator assumes for lists, maps
+	// and strings. Resources are declared as `dyn` in the CEL environment, so
+	// the estimator has no way to know how big a collection can get. Without
+	// an upper bound every comprehension would be estimated at MaxUint64.
+	estimatedMaxSize uint64 = 1000
+	// interruptCheckFrequency is the number of comprehension iterations
+	// between two checks of the evaluation context.
+	interruptCheckFrequency uint = 100
+)
+
+var (
+	// ErrCostLimitExceeded is returned when the estimated or the actual cost
+	// of an expression exceeds the configured cost limit.
+	ErrCostLimitExceeded = errors.New("CEL cost limit exceeded")
+	// ErrEvaluationTimeout is returned when the evaluation of an expression
+	// takes longer than the configured timeout.
+	ErrEvaluationTimeout = errors.New("CEL evaluation timed out")
+)
+
+// Limits bounds the resources an expression is allowed to consume. The same
+// limits are used to reject expressions when building a ResourceGroup, and to
+// interrupt the evaluation of expressions when reconciling instances.
+//
+// A zero value means no limit.
+type Limits struct {
+	// CostLimit is the maximum cost of a single expression. At build time it
+	// is compared against the worst case estimated cost, at runtime against
+	// the actual cost.
+	CostLimit uint64
+	// EvaluationTimeout is the maximum duration of a single expression
+	// evaluation.
+	EvaluationTimeout time.Duration
+}
+
+// DefaultLimits returns the default expression limits.
+func DefaultLimits() Limits {
+	return Limits{
+		CostLimit:         DefaultCostLimit,
+		EvaluationTimeout: DefaultEvaluationTimeout,
+	}
+}
+
+// CheckEstimatedCost estimates the worst case cost of a compiled expression
+// and returns an error wrapping ErrCostLimitExceeded if it is above the cost
+// limit.
+func (l Limits) CheckEstimatedCost(env *cel.Env, ast *cel.Ast) error {
+	if l.CostLimit == 0 {
+		return nil
+	}
+	estimate, err := env.EstimateCost(ast, &sizeEstimator{maxSize: estimatedMaxSize})
+	if err != nil {
+		return fmt.Errorf("failed to estimate expression cost: %w", err)
+	}
+	if estimate.Max > l.CostLimit {
+		return fmt.Errorf("%w: estimated cost %d is above the limit of %d", ErrCostLimitExceeded, estimate.Max, l.CostLimit)
+	}
+	return nil
+}
+
+// ProgramOptions returns the program options enforcing the limits at
+// evaluation time.
+func (l Limits) ProgramOptions() []cel.ProgramOption {
+	var opts []cel.ProgramOption
+	if l.CostLimit > 0 {
+		opts = append(opts, cel.CostLimit(l.CostLimit))
+	}
+	// Comprehensions check the evaluation context periodically, so that
+	// they can be interrupted by the evaluation timeout or by the caller.
+	opts = append(opts, cel.InterruptCheckFrequency(interruptCheckFrequency))
+	return opts
+}
+
+// Eval evaluates a program created with ProgramOptions. The evaluation is
+// cancelled if it exceeds the evaluation timeout or the cost limit, or if the
+// given context is done.
+func (l Limits) Eval(ctx context.Context, program cel.Program, vars map[string]interface{}) (ref.Val, error) {
+	evalCtx := ctx
+	if l.EvaluationTimeout > 0 {
+		var cancel context.CancelFunc
+		evalCtx, cancel = context.WithTimeout(ctx, l.EvaluationTimeout)
+		defer cancel()
+	}
+
+	val, _, err := program.ContextEval(evalCtx, vars)
+	if err != nil {
+		var cancelled interpreter.EvalCancelledError
+		if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+			return nil, fmt.Errorf("%w: actual cost is above the limit of %d", ErrCostLimitExceeded, l.CostLimit)
+		}
+		// Interrupted evaluations only report "operation interrupted", the
+		// contexts tell whether the caller gave up or the timeout expired.
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("CEL evaluation cancelled: %w", ctx.Err())
+		}
+		if evalCtx.Err() != nil {
+			return nil, fmt.Errorf("%w after %s", ErrEvaluationTimeout, l.EvaluationTimeout)
+		}
+		return nil, err
+	}
+	return val, nil
+}
+
+// IsLimitError returns true if the error is caused by an expression
+// exceeding its cost limit or evaluation timeout.
+func IsLimitError(err error) bool {
+	return errors.Is(err, ErrCostLimitExceeded) || errors.Is(err, ErrEvaluationTimeout)
+}
+
+// sizeEstimator is a checker.CostEstimator that assumes every collection
+// and string is at most maxSize long.
+type sizeEstimator struct {
+	maxSize uint64
+}
+
+func (e *sizeEstimator) EstimateSize(_ checker.AstNode) *checker.SizeEstimate {
+	return &checker.SizeEstimate{Min: 0, Max: e.maxSize}
+}
+
+func (e *sizeEstimator) EstimateCallCost(_, _ string, _ *checker.AstNode, _ []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quadraticExpression iterates over every pair of items, every pair matches
+// so it can't stop early.
+const quadraticExpression = "schema.items.all(x, schema.items.all(y, x >= 0 && y >= 0))"
+
+func compile(t *testing.T, expression string) (*cel.Env, *cel.Ast) {
+	t.Helper()
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	require.NoError(t, err)
+	ast, issues := env.Compile(expression)
+	require.NoError(t, issues.Err())
+	return env, ast
+}
+
+func program(t *testing.T, limits Limits, expression string) cel.Program {
+	t.Helper()
+	env, ast := compile(t, expression)
+	program, err := env.Program(ast, limits.ProgramOptions()...)
+	require.NoError(t, err)
+	return program
+}
+
+func items(n int) map[string]interface{} {
+	list := make([]interface{}, n)
+	for i := range list {
+		list[i] = int64(i)
+	}
+	return map[string]interface{}{
+		"schema": map[string]interface{}{"items": list},
+	}
+}
+
+func TestLimitsCheckEstimatedCost(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     Limits
+		expression string
+		wantErr    bool
+	}{
+		{
+			name:       "cheap expression",
+			limits:     Limits{CostLimit: 100},
+			expression: "schema.items.size() > 0",
+		},
+		{
+			name:       "estimated cost above the limit",
+			limits:     Limits{CostLimit: 100},
+			expression: quadraticExpression,
+			wantErr:    true,
+		},
+		{
+			name:       "default limit",
+			limits:     DefaultLimits(),
+			expression: "schema.items.all(x, x >= 0)",
+		},
+		{
+			name:       "no limit",
+			limits:     Limits{},
+			expression: quadraticExpression,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, ast := compile(t, tt.expression)
+			err := tt.limits.CheckEstimatedCost(env, ast)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrCostLimitExceeded)
+			assert.True(t, IsLimitError(err))
+		})
+	}
+}
+
+func TestLimitsEval(t *testing.T) {
+	limits := Limits{CostLimit: 100}
+	val, err := limits.Eval(context.Background(), program(t, limits, "schema.items.all(x, x >= 0)"), items(10))
+	require.NoError(t, err)
+	assert.Equal(t, true, val.Value())
+}
+
+func TestLimitsEvalCostLimit(t *testing.T) {
+	limits := Limits{CostLimit: 100}
+	_, err := limits.Eval(context.Background(), program(t, limits, quadraticExpression), items(100))
+	assert.ErrorIs(t, err, ErrCostLimitExceeded)
+	assert.True(t, IsLimitError(err))
+}
+
+func TestLimitsEvalTimeout(t *testing.T) {
+	limits := Limits{EvaluationTimeout: 10 * time.Millisecond}
+	start := time.Now()
+	// Going through every pair of items takes seconds, far longer than the
+	// timeout.
+	_, err := limits.Eval(context.Background(), program(t, limits, quadraticExpression), items(3000))
+	assert.ErrorIs(t, err, ErrEvaluationTimeout)
+	assert.True(t, IsLimitError(err))
+	assert.Less(t, time.Since(start), time.Second, "evaluation wasn't interrupted")
+}
+
+func TestLimitsEvalCancelled(t *testing.T) {
+	limits := Limits{EvaluationTimeout: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := limits.Eval(ctx, program(t, limits, quadraticExpression), items(3000))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrEvaluationTimeout)
+	assert.False(t, IsLimitError(err))
+	assert.Less(t, time.Since(start), time.Second, "evaluation wasn't interrupted")
+}
+
+func TestIsLimitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "cost limit", err: ErrCostLimitExceeded, want: true},
+		{name: "timeout", err: ErrEvaluationTimeout, want: true},
+		{name: "wrapped cost limit", err: fmt.Errorf("resource vpc: %w", ErrCostLimitExceeded), want: true},
+		{name: "wrapped timeout", err: fmt.Errorf("resource vpc: %w", ErrEvaluationTimeout), want: true},
+		{name: "cancelled", err: fmt.Errorf("CEL evaluation cancelled: %w", context.Canceled), want: false},
+		{name: "other error", err: errors.New("no such key: name"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLimitError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// accessReviewCacheSize is the maximum number of allowed permissions kept
+	// in the cache of a client set.
+	accessReviewCacheSize = 1024
+	// accessReviewCacheTTL is the duration after which an allowed permission
+	// is reviewed again.
+	accessReviewCacheTTL = time.Minute
+)
+
+// Permission is an action a client needs to be allowed to perform.
+type Permission struct {
+	// Verb is the kubernetes API verb, e.g "create".
+	Verb string
+	// Group is the API group of the resource.
+	Group string
+	// Resource is the plural name of the resource, e.g "deployments".
+	Resource string
+	// Namespace is the namespace of the resource. It is empty for cluster
+	// scoped resources.
+	Namespace string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource = p.Resource + "." + p.Group
+	}
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", p.Verb, resource, p.Namespace)
+}
+
+// MissingPermissions runs a SelfSubjectAccessReview for each of the given
+// permissions, with the identity of the client set, and returns the ones it
+// isn't allowed. Allowed permissions are cached for a short while, to avoid
+// reviewing them at every reconciliation.
+func (c *Set) MissingPermissions(ctx context.Context, permissions []Permission) ([]Permission, error) {
+	var missing []Permission
+	reviewed := make(map[Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		if reviewed[permission] {
+			continue
+		}
+		reviewed[permission] = true
+
+		if _, ok := c.accessReviewCache.Get(permission); ok {
+			continue
+		}
+
+		review, err := c.kubernetes.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx,
+			&authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Verb:      permission.Verb,
+						Group:     permission.Group,
+						Resource:  permission.Resource,
+						Namespace: permission.Namespace,
+					},
+				},
+			},
+			metav1.CreateOptions{},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to review permission to %s: %w", permission, err)
+		}
+
+		if !review.Status.Allowed {
+			missing = append(missing, permission)
+			continue
+		}
+		c.accessReviewCache.Add(permission, struct{}{}, accessReviewCacheTTL)
+	}
+	return missing, nil
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	var reviews atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review authorizationv1.SelfSubjectAccessReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reviews.Add(1)
+
+		// Only deleting is forbidden.
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb != "delete"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	set, err := NewSet(Config{RestConfig: &rest.Config{Host: server.URL}})
+	require.NoError(t, err)
+
+	permissions := []Permission{
+		{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "default"},
+		{Verb: "delete", Group: "apps", Resource: "deployments", Namespace: "default"},
+		{Verb: "create", Resource: "namespaces"},
+		{Verb: "create", Resource: "namespaces"},
+	}
+
+	missing, err := set.MissingPermissions(context.Background(), permissions)
+	require.NoError(t, err)
+	assert.Equal(t, []Permission{permissions[1]}, missing)
+	assert.Equal(t, "delete deployments.apps in namespace default", missing[0].String())
+	assert.Equal(t, int32(3), reviews.Load())
+
+	// Allowed permissions are cached, missing ones are reviewed again.
+	missing, err = set.MissingPermissions(context.Background(), permissions)
+	require.NoError(t, err)
+	assert.Equal(t, []Permission{permissions[1]}, missing)
+	assert.Equal(t, int32(4), reviews.Load())
+}
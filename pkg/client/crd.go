@@ -25,6 +25,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/awslabs/kro/pkg/metadata"
 )
 
 const (
@@ -38,12 +40,16 @@ var _ CRDClient = &CRDWrapper{}
 
 // CRDClient represents operations for managing CustomResourceDefinitions
 type CRDClient interface {
-	// EnsureCreated ensures a CRD exists and is ready
-	Ensure(ctx context.Context, crd v1.CustomResourceDefinition) error
+	// Ensure ensures a CRD exists, is up-to-date and is ready. It returns the
+	// changes made to the schema of an existing CRD.
+	Ensure(ctx context.Context, crd v1.CustomResourceDefinition, opts ...EnsureOption) (SchemaChanges, error)
 
 	// Delete removes a CRD if it exists
 	Delete(ctx context.Context, name string) error
 
+	// Release removes the claim of the given owner on a CRD, if it exists
+	Release(ctx context.Context, name string, owner string) error
+
 	// Get retrieves a CRD by name
 	Get(ctx context.Context, name string) (*v1.CustomResourceDefinition, error)
 }
@@ -89,30 +95,98 @@ func newCRDWrapper(cfg CRDWrapperConfig) *CRDWrapper {
 	}
 }
 
-// Ensure ensures a CRD exists, up-to-date, and is ready. This can be
-// a dangerous operation as it will update the CRD if it already exists.
+// EnsureOption configures how a CRD is ensured.
+type EnsureOption func(*ensureOptions)
+
+type ensureOptions struct {
+	allowBreakingChanges bool
+	owner                string
+}
+
+// AllowBreakingChanges lets Ensure update an existing CRD with breaking
+// schema changes, e.g removed fields or type changes.
+func AllowBreakingChanges(allow bool) EnsureOption {
+	return func(o *ensureOptions) {
+		o.allowBreakingChanges = allow
+	}
+}
+
+// WithOwner claims the CRD for the given owner, the namespace/name of a
+// resource group. The owner is recorded in the annotations of the CRD, and
+// Ensure refuses to update a CRD claimed by another owner.
+func WithOwner(owner string) EnsureOption {
+	return func(o *ensureOptions) {
+		o.owner = owner
+	}
+}
+
+// OwnerConflictError is returned when a CRD can't be ensured because it is
+// claimed by another owner.
+type OwnerConflictError struct {
+	Name  string
+	Owner string
+}
+
+func (e *OwnerConflictError) Error() string {
+	return fmt.Sprintf("CRD %s is already claimed by resource group %s", e.Name, e.Owner)
+}
+
+// Ensure ensures a CRD exists, up-to-date, and is ready. If the CRD already
+// exists, its schema is compared with the desired one, and the update is
+// refused with a BreakingChangesError if it introduces breaking changes,
+// unless they are explicitly allowed. The update is refused with an
+// OwnerConflictError if the CRD is claimed by another owner, CRDs without
+// an owner are adopted.
 //
-// The caller is responsible for ensuring the CRD, isn't introducing
-// breaking changes.
-func (w *CRDWrapper) Ensure(ctx context.Context, crd v1.CustomResourceDefinition) error {
-	_, err := w.Get(ctx, crd.Name)
+// The changes made to the schema of the existing CRD are returned.
+func (w *CRDWrapper) Ensure(ctx context.Context, crd v1.CustomResourceDefinition, opts ...EnsureOption) (_ SchemaChanges, err error) {
+	options := ensureOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	operation := crdOperationGet
+	defer func(start time.Time) { observeCRDEnsure(operation, start, err) }(time.Now())
+
+	if options.owner != "" {
+		annotations := make(map[string]string, len(crd.Annotations)+1)
+		for k, v := range crd.Annotations {
+			annotations[k] = v
+		}
+		annotations[metadata.ResourceGroupOwnerAnnotation] = options.owner
+		crd.Annotations = annotations
+	}
+
+	var changes SchemaChanges
+	live, err := w.Get(ctx, crd.Name)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to check for existing CRD: %w", err)
+			return nil, fmt.Errorf("failed to check for existing CRD: %w", err)
 		}
 
+		operation = crdOperationCreate
 		w.log.Info("Creating CRD", "name", crd.Name)
 		if err := w.create(ctx, crd); err != nil {
-			return fmt.Errorf("failed to create CRD: %w", err)
+			return nil, fmt.Errorf("failed to create CRD: %w", err)
 		}
 	} else {
-		w.log.Info("Updating existing CRD", "name", crd.Name)
+		if owner := live.Annotations[metadata.ResourceGroupOwnerAnnotation]; owner != "" && options.owner != "" && owner != options.owner {
+			return nil, &OwnerConflictError{Name: crd.Name, Owner: owner}
+		}
+
+		operation = crdOperationPatch
+		changes = DiffCRDSchemas(live, &crd)
+		if breaking := changes.Breaking(); len(breaking) > 0 && !options.allowBreakingChanges {
+			return changes, &BreakingChangesError{Name: crd.Name, Changes: breaking}
+		}
+
+		w.log.Info("Updating existing CRD", "name", crd.Name, "changes", len(changes))
 		if err := w.patch(ctx, crd); err != nil {
-			return fmt.Errorf("failed to patch CRD: %w", err)
+			return changes, fmt.Errorf("failed to patch CRD: %w", err)
 		}
 	}
 
-	return w.waitForReady(ctx, crd.Name)
+	return changes, w.waitForReady(ctx, crd.Name)
 }
 
 // Get retrieves a CRD by name
@@ -152,6 +226,38 @@ func (w *CRDWrapper) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+// Release removes the claim of the given owner on a CRD, so that another resource
+// group defining the same kind can claim it. CRDs claimed by another owner are
+// left untouched.
+func (w *CRDWrapper) Release(ctx context.Context, name string, owner string) error {
+	crd, err := w.Get(ctx, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get CRD: %w", err)
+	}
+	if crd.Annotations[metadata.ResourceGroupOwnerAnnotation] != owner {
+		return nil
+	}
+
+	w.log.Info("Releasing CRD", "name", name, "owner", owner)
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				metadata.ResourceGroupOwnerAnnotation: nil,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal CRD release patch: %w", err)
+	}
+	if _, err := w.client.Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to release CRD: %w", err)
+	}
+	return nil
+}
+
 // waitForReady waits for a CRD to become ready
 func (w *CRDWrapper) waitForReady(ctx context.Context, name string) error {
 	w.log.Info("Waiting for CRD to become ready", "name", name)
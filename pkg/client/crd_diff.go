@@ -0,0 +1,240 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+// SchemaChangeType is the kind of change made to a CRD schema.
+type SchemaChangeType string
+
+const (
+	// SchemaChangeFieldAdded is an optional field added to the schema.
+	SchemaChangeFieldAdded SchemaChangeType = "FieldAdded"
+	// SchemaChangeFieldRemoved is a field removed from the schema. Existing
+	// values of the field are pruned.
+	SchemaChangeFieldRemoved SchemaChangeType = "FieldRemoved"
+	// SchemaChangeTypeChanged is a field whose type changed. Existing values
+	// of the field don't validate anymore.
+	SchemaChangeTypeChanged SchemaChangeType = "TypeChanged"
+	// SchemaChangeFieldRequired is a field that is now required. Existing
+	// objects without the field don't validate anymore.
+	SchemaChangeFieldRequired SchemaChangeType = "FieldRequired"
+	// SchemaChangeFieldOptional is a required field that is now optional.
+	SchemaChangeFieldOptional SchemaChangeType = "FieldOptional"
+	// SchemaChangeVersionAdded is a version added to the CRD.
+	SchemaChangeVersionAdded SchemaChangeType = "VersionAdded"
+	// SchemaChangeVersionRemoved is a version removed from the CRD. Clients
+	// of the version are broken.
+	SchemaChangeVersionRemoved SchemaChangeType = "VersionRemoved"
+)
+
+// breakingSchemaChanges are the changes breaking the existing objects or
+// their clients.
+var breakingSchemaChanges = sets.New(
+	SchemaChangeFieldRemoved,
+	SchemaChangeTypeChanged,
+	SchemaChangeFieldRequired,
+	SchemaChangeVersionRemoved,
+)
+
+// SchemaChange is a change made to the schema of a CRD version.
+type SchemaChange struct {
+	// Version is the CRD version the change is made in.
+	Version string
+	// Path is the path of the changed field, e.g spec.replicas. It is empty
+	// for version changes.
+	Path string
+	// Type is the kind of change.
+	Type SchemaChangeType
+	// Details describes the change, e.g the old and new types of a field.
+	Details string
+}
+
+// IsBreaking returns true if the change breaks the existing objects or their
+// clients.
+func (c SchemaChange) IsBreaking() bool {
+	return breakingSchemaChanges.Has(c.Type)
+}
+
+func (c SchemaChange) String() string {
+	var b strings.Builder
+	b.WriteString(c.Version)
+	if c.Path != "" {
+		b.WriteString(" ")
+		b.WriteString(c.Path)
+	}
+	b.WriteString(": ")
+	b.WriteString(string(c.Type))
+	if c.Details != "" {
+		b.WriteString(" (")
+		b.WriteString(c.Details)
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// SchemaChanges is a list of changes made to the schema of a CRD.
+type SchemaChanges []SchemaChange
+
+// Breaking returns the breaking changes.
+func (c SchemaChanges) Breaking() SchemaChanges {
+	var breaking SchemaChanges
+	for _, change := range c {
+		if change.IsBreaking() {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+func (c SchemaChanges) String() string {
+	changes := make([]string, 0, len(c))
+	for _, change := range c {
+		changes = append(changes, change.String())
+	}
+	return strings.Join(changes, "; ")
+}
+
+// BreakingChangesError is returned when a CRD update is refused because it
+// introduces breaking changes.
+type BreakingChangesError struct {
+	Name    string
+	Changes SchemaChanges
+}
+
+func (e *BreakingChangesError) Error() string {
+	return fmt.Sprintf("refusing to update CRD %s with breaking changes: %s, set the %s annotation of the resource group to \"true\" to allow them",
+		e.Name, e.Changes, metadata.AllowBreakingChangesAnnotation)
+}
+
+// DiffCRDSchemas returns the changes made to the schemas of the versions of
+// the live CRD by the desired one. Changes are sorted by version and path.
+func DiffCRDSchemas(live, desired *v1.CustomResourceDefinition) SchemaChanges {
+	var changes SchemaChanges
+
+	liveVersions := crdVersionSchemas(live)
+	desiredVersions := crdVersionSchemas(desired)
+	for name, liveSchema := range liveVersions {
+		desiredSchema, ok := desiredVersions[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Version: name, Type: SchemaChangeVersionRemoved})
+			continue
+		}
+		changes = append(changes, diffSchemas(name, "", liveSchema, desiredSchema)...)
+	}
+	for name := range desiredVersions {
+		if _, ok := liveVersions[name]; !ok {
+			changes = append(changes, SchemaChange{Version: name, Type: SchemaChangeVersionAdded})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Version != changes[j].Version {
+			return changes[i].Version < changes[j].Version
+		}
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// crdVersionSchemas returns the OpenAPI schemas of the versions of a CRD,
+// indexed by version name.
+func crdVersionSchemas(crd *v1.CustomResourceDefinition) map[string]*v1.JSONSchemaProps {
+	schemas := make(map[string]*v1.JSONSchemaProps, len(crd.Spec.Versions))
+	for _, version := range crd.Spec.Versions {
+		var schema *v1.JSONSchemaProps
+		if version.Schema != nil {
+			schema = version.Schema.OpenAPIV3Schema
+		}
+		schemas[version.Name] = schema
+	}
+	return schemas
+}
+
+// diffSchemas recursively compares two schemas found at the given path.
+func diffSchemas(version, path string, live, desired *v1.JSONSchemaProps) SchemaChanges {
+	if live == nil || desired == nil {
+		return nil
+	}
+
+	if live.Type != desired.Type {
+		return SchemaChanges{{
+			Version: version,
+			Path:    path,
+			Type:    SchemaChangeTypeChanged,
+			Details: fmt.Sprintf("%s -> %s", live.Type, desired.Type),
+		}}
+	}
+
+	var changes SchemaChanges
+	for name, liveProperty := range live.Properties {
+		propertyPath := joinSchemaPath(path, name)
+		desiredProperty, ok := desired.Properties[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Version: version, Path: propertyPath, Type: SchemaChangeFieldRemoved})
+			continue
+		}
+		changes = append(changes, diffSchemas(version, propertyPath, &liveProperty, &desiredProperty)...)
+
+		wasRequired := slices.Contains(live.Required, name)
+		isRequired := slices.Contains(desired.Required, name)
+		switch {
+		case !wasRequired && isRequired:
+			changes = append(changes, SchemaChange{Version: version, Path: propertyPath, Type: SchemaChangeFieldRequired})
+		case wasRequired && !isRequired:
+			changes = append(changes, SchemaChange{Version: version, Path: propertyPath, Type: SchemaChangeFieldOptional})
+		}
+	}
+	for name := range desired.Properties {
+		if _, ok := live.Properties[name]; ok {
+			continue
+		}
+		propertyPath := joinSchemaPath(path, name)
+		if slices.Contains(desired.Required, name) {
+			changes = append(changes, SchemaChange{
+				Version: version,
+				Path:    propertyPath,
+				Type:    SchemaChangeFieldRequired,
+				Details: "new field",
+			})
+			continue
+		}
+		changes = append(changes, SchemaChange{Version: version, Path: propertyPath, Type: SchemaChangeFieldAdded})
+	}
+
+	if live.Items != nil && desired.Items != nil {
+		changes = append(changes, diffSchemas(version, path+"[]", live.Items.Schema, desired.Items.Schema)...)
+	}
+	if live.AdditionalProperties != nil && desired.AdditionalProperties != nil {
+		changes = append(changes, diffSchemas(version, path+"{}", live.AdditionalProperties.Schema, desired.AdditionalProperties.Schema)...)
+	}
+	return changes
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,190 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+func newTestCRD(versions map[string]v1.JSONSchemaProps) *v1.CustomResourceDefinition {
+	crd := &v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapps.kro.run"},
+	}
+	for name, spec := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, v1.CustomResourceDefinitionVersion{
+			Name: name,
+			Schema: &v1.CustomResourceValidation{
+				OpenAPIV3Schema: &v1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]v1.JSONSchemaProps{
+						"spec": spec,
+					},
+				},
+			},
+		})
+	}
+	return crd
+}
+
+func TestDiffCRDSchemas(t *testing.T) {
+	liveSpec := v1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]v1.JSONSchemaProps{
+			"name":     {Type: "string"},
+			"replicas": {Type: "integer"},
+			"ports": {
+				Type: "array",
+				Items: &v1.JSONSchemaPropsOrArray{Schema: &v1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]v1.JSONSchemaProps{
+						"port": {Type: "integer"},
+					},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		desired      map[string]v1.JSONSchemaProps
+		wantChanges  []string
+		wantBreaking int
+	}{
+		{
+			name:    "no changes",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": liveSpec},
+		},
+		{
+			name: "added optional field",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": func() v1.JSONSchemaProps {
+				spec := *liveSpec.DeepCopy()
+				spec.Properties["image"] = v1.JSONSchemaProps{Type: "string"}
+				return spec
+			}()},
+			wantChanges: []string{"v1alpha1 spec.image: FieldAdded"},
+		},
+		{
+			name: "added required field",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": func() v1.JSONSchemaProps {
+				spec := *liveSpec.DeepCopy()
+				spec.Properties["image"] = v1.JSONSchemaProps{Type: "string"}
+				spec.Required = append(spec.Required, "image")
+				return spec
+			}()},
+			wantChanges:  []string{"v1alpha1 spec.image: FieldRequired (new field)"},
+			wantBreaking: 1,
+		},
+		{
+			name: "existing field required",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": func() v1.JSONSchemaProps {
+				spec := *liveSpec.DeepCopy()
+				spec.Required = append(spec.Required, "replicas")
+				return spec
+			}()},
+			wantChanges:  []string{"v1alpha1 spec.replicas: FieldRequired"},
+			wantBreaking: 1,
+		},
+		{
+			name: "required field optional",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": func() v1.JSONSchemaProps {
+				spec := *liveSpec.DeepCopy()
+				spec.Required = nil
+				return spec
+			}()},
+			wantChanges: []string{"v1alpha1 spec.name: FieldOptional"},
+		},
+		{
+			name: "removed and retyped fields",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha1": func() v1.JSONSchemaProps {
+				spec := *liveSpec.DeepCopy()
+				delete(spec.Properties, "replicas")
+				spec.Properties["ports"].Items.Schema.Properties["port"] = v1.JSONSchemaProps{Type: "string"}
+				return spec
+			}()},
+			wantChanges: []string{
+				"v1alpha1 spec.ports[].port: TypeChanged (integer -> string)",
+				"v1alpha1 spec.replicas: FieldRemoved",
+			},
+			wantBreaking: 2,
+		},
+		{
+			name:    "version replaced",
+			desired: map[string]v1.JSONSchemaProps{"v1alpha2": liveSpec},
+			wantChanges: []string{
+				"v1alpha1: VersionRemoved",
+				"v1alpha2: VersionAdded",
+			},
+			wantBreaking: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := newTestCRD(map[string]v1.JSONSchemaProps{"v1alpha1": liveSpec})
+			changes := DiffCRDSchemas(live, newTestCRD(tt.desired))
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			assert.Equal(t, tt.wantChanges, got)
+			assert.Len(t, changes.Breaking(), tt.wantBreaking)
+		})
+	}
+}
+
+func TestEnsureRefusesBreakingChanges(t *testing.T) {
+	live := newTestCRD(map[string]v1.JSONSchemaProps{"v1alpha1": {
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"replicas": {Type: "integer"},
+		},
+	}})
+	desired := newTestCRD(map[string]v1.JSONSchemaProps{"v1alpha1": {
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"replicas": {Type: "string"},
+		},
+	}})
+
+	w := &CRDWrapper{
+		client: fake.NewSimpleClientset(live).ApiextensionsV1().CustomResourceDefinitions(),
+		log:    logr.Discard(),
+	}
+
+	changes, err := w.Ensure(context.Background(), *desired)
+	require.Error(t, err)
+	var breakingErr *BreakingChangesError
+	require.True(t, errors.As(err, &breakingErr))
+	assert.Equal(t, changes, breakingErr.Changes)
+	assert.Contains(t, err.Error(), "spec.replicas: TypeChanged (integer -> string)")
+	assert.Contains(t, err.Error(), metadata.AllowBreakingChangesAnnotation)
+
+	// The live CRD is left untouched.
+	current, err := w.Get(context.Background(), live.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "integer", current.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["replicas"].Type)
+}
@@ -0,0 +1,147 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+func TestCRDWrapper_EnsureOwner(t *testing.T) {
+	spec := v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"replicas": {Type: "integer"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		liveOwner string
+		wantOwner string
+		wantErr   bool
+	}{
+		{
+			name:      "unclaimed CRD is adopted",
+			wantOwner: "default/webapp",
+		},
+		{
+			name:      "CRD claimed by the same owner",
+			liveOwner: "default/webapp",
+			wantOwner: "default/webapp",
+		},
+		{
+			name:      "CRD claimed by another owner",
+			liveOwner: "other/webapp",
+			wantOwner: "other/webapp",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := newTestCRD(map[string]v1.JSONSchemaProps{"v1alpha1": spec})
+			if tt.liveOwner != "" {
+				live.Annotations = map[string]string{metadata.ResourceGroupOwnerAnnotation: tt.liveOwner}
+			}
+			established := []v1.CustomResourceDefinitionCondition{
+				{Type: v1.Established, Status: v1.ConditionTrue},
+			}
+			live.Status.Conditions = established
+			// The fake client doesn't serve the status subresource, patches
+			// overwrite the status of the live CRD.
+			desired := newTestCRD(map[string]v1.JSONSchemaProps{"v1alpha1": spec})
+			desired.Status.Conditions = established
+
+			w := &CRDWrapper{
+				client:       fake.NewSimpleClientset(live).ApiextensionsV1().CustomResourceDefinitions(),
+				log:          logr.Discard(),
+				pollInterval: time.Millisecond,
+				timeout:      time.Second,
+			}
+
+			_, err := w.Ensure(context.Background(), *desired, WithOwner("default/webapp"))
+			if tt.wantErr {
+				var conflictErr *OwnerConflictError
+				require.True(t, errors.As(err, &conflictErr))
+				assert.Equal(t, tt.liveOwner, conflictErr.Owner)
+			} else {
+				require.NoError(t, err)
+			}
+
+			current, err := w.Get(context.Background(), live.Name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOwner, current.Annotations[metadata.ResourceGroupOwnerAnnotation])
+		})
+	}
+}
+
+func TestCRDWrapper_Release(t *testing.T) {
+	tests := []struct {
+		name      string
+		liveOwner string
+		wantOwner string
+	}{
+		{
+			name:      "CRD claimed by the owner",
+			liveOwner: "default/webapp",
+		},
+		{
+			name:      "CRD claimed by another owner",
+			liveOwner: "other/webapp",
+			wantOwner: "other/webapp",
+		},
+		{
+			name: "unclaimed CRD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := newTestCRD(nil)
+			live.Annotations = map[string]string{"example.com/note": "kept"}
+			if tt.liveOwner != "" {
+				live.Annotations[metadata.ResourceGroupOwnerAnnotation] = tt.liveOwner
+			}
+
+			w := &CRDWrapper{
+				client: fake.NewSimpleClientset(live).ApiextensionsV1().CustomResourceDefinitions(),
+				log:    logr.Discard(),
+			}
+
+			require.NoError(t, w.Release(context.Background(), live.Name, "default/webapp"))
+			current, err := w.Get(context.Background(), live.Name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOwner, current.Annotations[metadata.ResourceGroupOwnerAnnotation])
+			assert.Equal(t, "kept", current.Annotations["example.com/note"])
+		})
+	}
+
+	// Missing CRDs are ignored.
+	w := &CRDWrapper{
+		client: fake.NewSimpleClientset().ApiextensionsV1().CustomResourceDefinitions(),
+		log:    logr.Discard(),
+	}
+	require.NoError(t, w.Release(context.Background(), "missing.kro.run", "default/webapp"))
+}
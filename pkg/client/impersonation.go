@@ -0,0 +1,72 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"time"
+
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// DefaultImpersonationCacheSize is the default maximum number of
+	// impersonated client sets kept in the cache.
+	DefaultImpersonationCacheSize = 128
+	// DefaultImpersonationCacheTTL is the default duration after which an
+	// impersonated client set is evicted from the cache.
+	DefaultImpersonationCacheTTL = 10 * time.Minute
+)
+
+// impersonationCache is an LRU cache of impersonated client sets, keyed by
+// the impersonated user. Reusing the client sets avoids building new clients
+// at every reconciliation, and keeps the HTTP connections alive. Entries expire
+// after a TTL, so that clients are eventually rebuilt.
+type impersonationCache struct {
+	ttl   time.Duration
+	cache *utilcache.LRUExpireCache
+}
+
+func newImpersonationCache(size int, ttl time.Duration) *impersonationCache {
+	if size <= 0 {
+		size = DefaultImpersonationCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultImpersonationCacheTTL
+	}
+	return &impersonationCache{
+		ttl:   ttl,
+		cache: utilcache.NewLRUExpireCache(size),
+	}
+}
+
+// WithCachedImpersonation returns a client set impersonating the given user,
+// reusing a previously created one if it hasn't expired yet. The returned
+// boolean reports whether the client set was found in the cache.
+func (c *Set) WithCachedImpersonation(user string) (*Set, bool, error) {
+	if c.impersonationCache == nil {
+		set, err := c.WithImpersonation(user)
+		return set, false, err
+	}
+
+	if set, ok := c.impersonationCache.cache.Get(user); ok {
+		return set.(*Set), true, nil
+	}
+
+	set, err := c.WithImpersonation(user)
+	if err != nil {
+		return nil, false, err
+	}
+	c.impersonationCache.cache.Add(user, set, c.impersonationCache.ttl)
+	return set, false, nil
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestWithCachedImpersonation(t *testing.T) {
+	set, err := NewSet(Config{
+		RestConfig:             &rest.Config{Host: "https://localhost:6443"},
+		ImpersonationCacheSize: 1,
+		ImpersonationCacheTTL:  time.Hour,
+	})
+	require.NoError(t, err)
+
+	alice, hit, err := set.WithCachedImpersonation("alice")
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, "alice", alice.config.Impersonate.UserName)
+	assert.Nil(t, alice.impersonationCache)
+
+	cached, hit, err := set.WithCachedImpersonation("alice")
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Same(t, alice, cached)
+
+	// The cache holds a single entry, bob evicts alice.
+	_, hit, err = set.WithCachedImpersonation("bob")
+	require.NoError(t, err)
+	assert.False(t, hit)
+
+	_, hit, err = set.WithCachedImpersonation("alice")
+	require.NoError(t, err)
+	assert.False(t, hit)
+}
+
+func TestWithCachedImpersonationExpiration(t *testing.T) {
+	set, err := NewSet(Config{
+		RestConfig:            &rest.Config{Host: "https://localhost:6443"},
+		ImpersonationCacheTTL: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, hit, err := set.WithCachedImpersonation("alice")
+	require.NoError(t, err)
+	assert.False(t, hit)
+
+	time.Sleep(10 * time.Millisecond)
+
+	_, hit, err = set.WithCachedImpersonation("alice")
+	require.NoError(t, err)
+	assert.False(t, hit)
+}
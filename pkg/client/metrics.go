@@ -0,0 +1,55 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricCRDEnsureDuration tracks the duration of the CRD ensure operations,
+	// until the CRD is established
+	MetricCRDEnsureDuration = "crd_ensure_duration_seconds"
+)
+
+// Operations made when ensuring a CRD.
+const (
+	crdOperationGet    = "get"
+	crdOperationCreate = "create"
+	crdOperationPatch  = "patch"
+)
+
+var crdEnsureDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    MetricCRDEnsureDuration,
+		Help:    "Duration of the CRD ensure operations by operation and result",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	},
+	[]string{"operation", "result"},
+)
+
+func observeCRDEnsure(operation string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	crdEnsureDuration.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+}
+
+func init() {
+	metrics.Registry.MustRegister(crdEnsureDuration)
+}
@@ -13,7 +13,10 @@
 package client
 
 import (
+	"time"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -26,6 +29,12 @@ type Set struct {
 	kubernetes      *kubernetes.Clientset
 	dynamic         *dynamic.DynamicClient
 	apiExtensionsV1 *apiextensionsv1.ApiextensionsV1Client
+	// impersonationCache holds the client sets created by
+	// WithCachedImpersonation. It is nil for impersonated client sets.
+	impersonationCache *impersonationCache
+	// accessReviewCache holds the permissions the identity of the client set
+	// was recently allowed.
+	accessReviewCache *utilcache.LRUExpireCache
 }
 
 // Config holds configuration for client creation
@@ -34,6 +43,12 @@ type Config struct {
 	ImpersonateUser string
 	QPS             float32
 	Burst           int
+	// ImpersonationCacheSize is the maximum number of impersonated client sets
+	// kept in the cache. Defaults to DefaultImpersonationCacheSize.
+	ImpersonationCacheSize int
+	// ImpersonationCacheTTL is the duration after which an impersonated client
+	// set is evicted from the cache. Defaults to DefaultImpersonationCacheTTL.
+	ImpersonationCacheTTL time.Duration
 }
 
 // NewSet creates a new client Set with the given config
@@ -64,10 +79,16 @@ func NewSet(cfg Config) (*Set, error) {
 	}
 	config.UserAgent = "kro/0.1.0"
 
-	c := &Set{config: config}
+	c := &Set{
+		config:            config,
+		accessReviewCache: utilcache.NewLRUExpireCache(accessReviewCacheSize),
+	}
 	if err := c.init(); err != nil {
 		return nil, err
 	}
+	if cfg.ImpersonateUser == "" {
+		c.impersonationCache = newImpersonationCache(cfg.ImpersonationCacheSize, cfg.ImpersonationCacheTTL)
+	}
 
 	return c, nil
 }
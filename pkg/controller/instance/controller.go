@@ -16,6 +16,7 @@ package instance
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,13 +25,24 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/awslabs/kro/api/v1alpha1"
 	kroclient "github.com/awslabs/kro/pkg/client"
 	"github.com/awslabs/kro/pkg/graph"
 	"github.com/awslabs/kro/pkg/metadata"
+	"github.com/awslabs/kro/pkg/tracing"
+)
+
+const (
+	// DefaultReconcileInterval is the default interval at which active instances
+	// are reconciled again.
+	DefaultReconcileInterval = 5 * time.Minute
+	// reconcileIntervalJitter is the maximum factor by which the reconcile
+	// interval is extended, so that the instances of a same ResourceGroup
+	// don't all get reconciled at once.
+	reconcileIntervalJitter = 0.1
 )
 
 // ReconcileConfig holds configuration parameters for the recnociliation process.
@@ -47,6 +59,13 @@ type ReconcileConfig struct {
 	// TODO(a-hilaly): need to define think the different deletion policies we need to
 	// support.
 	DeletionPolicy string
+	// Paused pauses the reconciliation of all the instances, as the paused
+	// annotation does for a single instance.
+	Paused bool
+	// ReconcileInterval is the interval at which active instances are
+	// reconciled again, unless overridden by the reconcile interval annotation
+	// of the instance. Zero disables the periodic reconciliation.
+	ReconcileInterval time.Duration
 }
 
 // Controller manages the reconciliation of a single instance of a ResourceGroup,
@@ -88,6 +107,17 @@ type Controller struct {
 	reconcileConfig ReconcileConfig
 	// defaultServiceAccounts is a map of service accounts to use for controller impersonation.
 	defaultServiceAccounts map[string]string
+	// allowedServiceAccounts is the list of service accounts instances can request
+	// through the service account annotation.
+	allowedServiceAccounts []string
+	// recorder records events on the instances.
+	recorder record.EventRecorder
+	// resourceGroup is the name of the ResourceGroup, used to label the
+	// instance metrics.
+	resourceGroup string
+	// skippedResources tracks the resources skipped by the last reconciliation
+	// of each instance, so that skipped resources are only reported once.
+	skippedResources *skippedResources
 }
 
 // NewController creates a new Controller instance.
@@ -98,7 +128,9 @@ func NewController(
 	rg *graph.Graph,
 	clientSet *kroclient.Set,
 	defaultServiceAccounts map[string]string,
+	allowedServiceAccounts []string,
 	instanceLabeler metadata.Labeler,
+	recorder record.EventRecorder,
 ) *Controller {
 	return &Controller{
 		log:                    log,
@@ -108,31 +140,45 @@ func NewController(
 		instanceLabeler:        instanceLabeler,
 		reconcileConfig:        reconcileConfig,
 		defaultServiceAccounts: defaultServiceAccounts,
+		allowedServiceAccounts: allowedServiceAccounts,
+		recorder:               recorder,
+		resourceGroup:          instanceLabeler.Labels()[metadata.ResourceGroupNameLabel],
+		skippedResources:       newSkippedResources(),
 	}
 }
 
 // Reconcile is a handler function that reconciles the instance and its sub-resources.
-func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
+func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error) {
 	namespace, name := getNamespaceName(req)
 
 	log := c.log.WithValues("namespace", namespace, "name", name)
 
+	ctx, span := tracing.Start(ctx, "instance.Reconcile",
+		tracing.InstanceNamespaceKey.String(namespace),
+		tracing.InstanceNameKey.String(name),
+		tracing.ResourceGVRKey.String(c.gvr.String()),
+	)
+	defer func() { endReconcileSpan(span, err) }()
+
 	instance, err := c.clientSet.Dynamic().Resource(c.gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Instance not found, it may have been deleted")
+			instances.forget(c.resourceGroup, namespace+"/"+name)
+			c.skippedResources.forget(namespace + "/" + name)
 			return nil
 		}
 		log.Error(err, "Failed to get instance")
 		return nil
 	}
+	span.SetAttributes(tracing.InstanceUIDKey.String(string(instance.GetUID())))
 
 	// This is one of the main reasons why we're splitting the controller into
 	// two parts. The instanciator is responsible for creating a new runtime
 	// instance of the resource group. The instance graph reconciler is responsible
 	// for reconciling the instance and its sub-resources, while keeping the same
 	// runtime object in it's fields.
-	rgRuntime, err := c.rg.NewGraphRuntime(instance)
+	rgRuntime, err := c.rg.NewGraphRuntime(ctx, instance)
 	if err != nil {
 		return fmt.Errorf("failed to create runtime resource group: %w", err)
 	}
@@ -143,8 +189,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 	}
 
 	// If possible, use a service account to create the execution client
-	// TODO(a-hilaly): client caching
-	executionClient, err := c.getExecutionClient(namespace)
+	executionClient, err := c.getExecutionClient(ctx, namespace, instance.GetAnnotations()[metadata.ServiceAccountAnnotation])
 	if err != nil {
 		return fmt.Errorf("failed to create execution client: %w", err)
 	}
@@ -152,11 +197,15 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 	instanceGraphReconciler := &instanceGraphReconciler{
 		log:                         log,
 		gvr:                         c.gvr,
-		client:                      executionClient,
+		client:                      executionClient.Dynamic(),
+		clientSet:                   executionClient,
 		runtime:                     rgRuntime,
 		instanceLabeler:             c.instanceLabeler,
 		instanceSubResourcesLabeler: instanceSubResourcesLabeler,
 		reconcileConfig:             c.reconcileConfig,
+		recorder:                    c.recorder,
+		resourceGroup:               c.resourceGroup,
+		skippedResources:            c.skippedResources,
 		// Fresh instance state at each reconciliation loop.
 		state: newInstanceState(),
 	}
@@ -185,14 +234,24 @@ const (
 )
 
 // getExecutionClient determines the execution client to use for the instance.
-// If the instance is created in a namespace of which a service account is specified,
-// the execution client will be created using the service account. If no service account
-// is specified for the namespace, the default client will be used.
-func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, error) {
+// If the instance requests a service account, the execution client will be created
+// using it. Otherwise, if the instance is created in a namespace of which a service
+// account is specified, the execution client will be created using the service
+// account. If no service account is specified for the namespace, the default client
+// will be used.
+func (c *Controller) getExecutionClient(
+	ctx context.Context,
+	namespace string,
+	requestedServiceAccount string,
+) (*kroclient.Set, error) {
+	if requestedServiceAccount != "" {
+		return c.getRequestedServiceAccountClient(ctx, namespace, requestedServiceAccount)
+	}
+
 	// if no service accounts are specified, use the default client
 	if len(c.defaultServiceAccounts) == 0 {
 		c.log.V(1).Info("no service accounts configured, using default client")
-		return c.clientSet.Dynamic(), nil
+		return c.clientSet, nil
 	}
 
 	timer := prometheus.NewTimer(impersonationDuration.WithLabelValues(namespace, ""))
@@ -206,14 +265,15 @@ func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, er
 			return nil, fmt.Errorf("invalid service account configuration: %w", err)
 		}
 
-		pivotedClient, err := c.clientSet.WithImpersonation(userName)
+		pivotedClient, hit, err := c.clientSet.WithCachedImpersonation(userName)
 		if err != nil {
 			c.handleImpersonateError(namespace, sa, err)
 			return nil, fmt.Errorf("failed to create impersonated client: %w", err)
 		}
+		recordImpersonationCacheLookup(namespace, sa, hit)
 
 		impersonationTotal.WithLabelValues(namespace, sa, "success").Inc()
-		return pivotedClient.Dynamic(), nil
+		return pivotedClient, nil
 	}
 
 	// Check for default service account (marked by "*")
@@ -224,19 +284,63 @@ func (c *Controller) getExecutionClient(namespace string) (dynamic.Interface, er
 			return nil, fmt.Errorf("invalid default service account configuration: %w", err)
 		}
 
-		pivotedClient, err := c.clientSet.WithImpersonation(userName)
+		pivotedClient, hit, err := c.clientSet.WithCachedImpersonation(userName)
 		if err != nil {
 			c.handleImpersonateError(namespace, defaultSA, err)
 			return nil, fmt.Errorf("failed to create impersonated client with default SA: %w", err)
 		}
+		recordImpersonationCacheLookup(namespace, defaultSA, hit)
 
 		impersonationTotal.WithLabelValues(namespace, defaultSA, "success").Inc()
-		return pivotedClient.Dynamic(), nil
+		return pivotedClient, nil
 	}
 
 	impersonationTotal.WithLabelValues(namespace, "", "default").Inc()
 	// Fallback to the default client
-	return c.clientSet.Dynamic(), nil
+	return c.clientSet, nil
+}
+
+// getRequestedServiceAccountClient creates an execution client impersonating the
+// service account requested by the instance. The service account must be allowed
+// by the resource group, and exist in the namespace of the instance.
+func (c *Controller) getRequestedServiceAccountClient(
+	ctx context.Context,
+	namespace string,
+	sa string,
+) (*kroclient.Set, error) {
+	timer := prometheus.NewTimer(impersonationDuration.WithLabelValues(namespace, sa))
+	defer timer.ObserveDuration()
+
+	if !slices.Contains(c.allowedServiceAccounts, sa) {
+		recordImpersonateError(namespace, sa, errorInvalidSA)
+		return nil, fmt.Errorf("service account %s is not allowed by the resource group", sa)
+	}
+
+	_, err := c.clientSet.Kubernetes().CoreV1().ServiceAccounts(namespace).Get(ctx, sa, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			recordImpersonateError(namespace, sa, errorInvalidSA)
+			return nil, fmt.Errorf("service account %s/%s not found", namespace, sa)
+		}
+		c.handleImpersonateError(namespace, sa, err)
+		return nil, fmt.Errorf("failed to get service account %s/%s: %w", namespace, sa, err)
+	}
+
+	userName, err := getServiceAccountUserName(namespace, sa)
+	if err != nil {
+		c.handleImpersonateError(namespace, sa, err)
+		return nil, fmt.Errorf("invalid service account configuration: %w", err)
+	}
+
+	pivotedClient, hit, err := c.clientSet.WithCachedImpersonation(userName)
+	if err != nil {
+		c.handleImpersonateError(namespace, sa, err)
+		return nil, fmt.Errorf("failed to create impersonated client: %w", err)
+	}
+	recordImpersonationCacheLookup(namespace, sa, hit)
+
+	impersonationTotal.WithLabelValues(namespace, sa, "success").Inc()
+	return pivotedClient, nil
 }
 
 // handleImpersonateError logs the error and records the error in the metrics
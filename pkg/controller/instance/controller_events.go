@@ -0,0 +1,148 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/awslabs/kro/pkg/requeue"
+)
+
+// Reasons of the events recorded on instances.
+const (
+	EventReasonResourceCreated        = "ResourceCreated"
+	EventReasonResourceCreationFailed = "ResourceCreationFailed"
+	EventReasonResourceDeleted        = "ResourceDeleted"
+	EventReasonResourceDeletionFailed = "ResourceDeletionFailed"
+	EventReasonResourceSkipped        = "ResourceSkipped"
+	EventReasonStateChanged           = "StateChanged"
+	EventReasonMissingPermissions     = "MissingPermissions"
+	EventReasonReconciliationFailed   = "ReconciliationFailed"
+	EventReasonPaused                 = "Paused"
+	EventReasonResourceFailed         = "ResourceFailed"
+)
+
+// recordEvent records an event on the instance, so that it can be found with
+// kubectl describe.
+func (igr *instanceGraphReconciler) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	igr.recorder.Eventf(igr.runtime.GetInstance(), eventType, reason, messageFmt, args...)
+}
+
+// recordResourceEvent records an event about one of the resources of the
+// instance.
+func (igr *instanceGraphReconciler) recordResourceEvent(
+	eventType, reason string,
+	resourceID string,
+	resource *unstructured.Unstructured,
+	messageFmt string,
+	args ...interface{},
+) {
+	target := resourceID
+	if resource != nil {
+		target = fmt.Sprintf("%s %s (%s)", resource.GetKind(), resource.GetName(), resourceID)
+	}
+	igr.recordEvent(eventType, reason, "%s: %s", target, fmt.Sprintf(messageFmt, args...))
+}
+
+// recordReconciliationEvents records the state changes and the errors of a
+// reconciliation.
+func (igr *instanceGraphReconciler) recordReconciliationEvents(previousState string) {
+	if igr.state.State != previousState {
+		eventType := corev1.EventTypeNormal
+		if igr.state.State == InstanceStateError || igr.state.State == InstanceStateDegraded {
+			eventType = corev1.EventTypeWarning
+		}
+		igr.recordEvent(eventType, EventReasonStateChanged,
+			"Instance state changed from %s to %s", stateOrUnknown(previousState), igr.state.State)
+	}
+
+	err := igr.state.ReconcileErr
+	if err == nil {
+		return
+	}
+	var missingPermissionsErr *MissingPermissionsError
+	if errors.As(err, &missingPermissionsErr) {
+		igr.recordEvent(corev1.EventTypeWarning, EventReasonMissingPermissions, "%s", missingPermissionsErr.Error())
+		return
+	}
+	var resourceFailedErr *ResourceFailedError
+	if errors.As(err, &resourceFailedErr) {
+		// Already recorded on the failed resource.
+		return
+	}
+	switch err.(type) {
+	case *requeue.NoRequeue, *requeue.RequeueNeeded, *requeue.RequeueNeededAfter:
+		// Requeues are part of the normal lifecycle, e.g waiting for a
+		// resource to become ready.
+		return
+	}
+	igr.recordEvent(corev1.EventTypeWarning, EventReasonReconciliationFailed, "%s", err.Error())
+}
+
+func stateOrUnknown(state string) string {
+	if state == "" {
+		return "UNKNOWN"
+	}
+	return state
+}
+
+// skippedResources tracks the resources skipped by the last reconciliation of
+// each instance, keyed by namespace/name. Resources whose includeWhen expressions
+// are false are skipped at every reconciliation, the ResourceSkipped event is
+// only recorded when they start being skipped.
+type skippedResources struct {
+	mu        sync.Mutex
+	instances map[string]map[string]bool
+}
+
+func newSkippedResources() *skippedResources {
+	return &skippedResources{instances: make(map[string]map[string]bool)}
+}
+
+// skip marks the resource of the instance as skipped, and reports whether it
+// wasn't skipped yet.
+func (s *skippedResources) skip(instance, resourceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	skipped, ok := s.instances[instance]
+	if !ok {
+		skipped = make(map[string]bool)
+		s.instances[instance] = skipped
+	}
+	if skipped[resourceID] {
+		return false
+	}
+	skipped[resourceID] = true
+	return true
+}
+
+// include marks the resource of the instance as no longer skipped.
+func (s *skippedResources) include(instance, resourceID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.instances[instance], resourceID)
+}
+
+// forget stops tracking the resources of a deleted instance.
+func (s *skippedResources) forget(instance string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.instances, instance)
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/awslabs/kro/pkg/requeue"
+)
+
+// drainEvents returns the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func newEventsReconciler(rt *fakeRuntime, skipped *skippedResources) (*instanceGraphReconciler, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(10)
+	return &instanceGraphReconciler{
+		log:              logr.Discard(),
+		runtime:          rt,
+		recorder:         recorder,
+		state:            newInstanceState(),
+		skippedResources: skipped,
+	}, recorder
+}
+
+func TestResourceSkippedEvents(t *testing.T) {
+	rt := &fakeRuntime{
+		instance: newObject("my-app", "default"),
+		order:    []string{"monitor"},
+		excluded: map[string]error{"monitor": nil},
+	}
+	skipped := newSkippedResources()
+
+	reconcile := func() ([]string, error) {
+		igr, recorder := newEventsReconciler(rt, skipped)
+		err := igr.reconcileResource(context.Background(), "monitor")
+		return drainEvents(recorder), err
+	}
+
+	// The resource starts being skipped.
+	events, err := reconcile()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Normal ResourceSkipped monitor: skipped, includeWhen expressions are false"}, events)
+
+	// It is still skipped by the next reconciliations.
+	events, err = reconcile()
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, []string{"monitor", "monitor"}, rt.ignored)
+
+	// It is included, and waits on its dependencies.
+	delete(rt.excluded, "monitor")
+	events, err = reconcile()
+	var requeueErr *requeue.RequeueNeededAfter
+	assert.True(t, errors.As(err, &requeueErr))
+	assert.Empty(t, events)
+
+	// It is skipped again.
+	rt.excluded["monitor"] = errors.New("no such key: enabled")
+	events, err = reconcile()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Warning ResourceSkipped monitor: skipped, includeWhen expressions failed: no such key: enabled"}, events)
+
+	// Deleted instances are forgotten.
+	skipped.forget("default/my-app")
+	events, err = reconcile()
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestRecordReconciliationEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		previousState string
+		state         string
+		reconcileErr  error
+		wantEvents    []string
+	}{
+		{
+			name:          "unchanged state",
+			previousState: InstanceStateActive,
+			state:         InstanceStateActive,
+		},
+		{
+			name:          "state changed",
+			previousState: InstanceStateInProgress,
+			state:         InstanceStateActive,
+			wantEvents:    []string{"Normal StateChanged Instance state changed from IN_PROGRESS to ACTIVE"},
+		},
+		{
+			name:       "first state",
+			state:      InstanceStateInProgress,
+			wantEvents: []string{"Normal StateChanged Instance state changed from UNKNOWN to IN_PROGRESS"},
+		},
+		{
+			name:          "degraded state",
+			previousState: InstanceStateInProgress,
+			state:         InstanceStateDegraded,
+			wantEvents:    []string{"Warning StateChanged Instance state changed from IN_PROGRESS to DEGRADED"},
+		},
+		{
+			name:          "requeued reconciliation",
+			previousState: InstanceStateInProgress,
+			state:         InstanceStateInProgress,
+			reconcileErr:  requeue.NeededAfter(errors.New("resource not ready"), 0),
+		},
+		{
+			name:          "failed reconciliation",
+			previousState: InstanceStateError,
+			state:         InstanceStateError,
+			reconcileErr:  errors.New("connection refused"),
+			wantEvents:    []string{"Warning ReconciliationFailed connection refused"},
+		},
+		{
+			name:          "missing permissions",
+			previousState: InstanceStateInProgress,
+			state:         InstanceStateInProgress,
+			reconcileErr:  &MissingPermissionsError{},
+			wantEvents:    []string{"Warning MissingPermissions missing permissions: "},
+		},
+		{
+			name:          "failed resource",
+			previousState: InstanceStateError,
+			state:         InstanceStateError,
+			reconcileErr:  requeue.None(&ResourceFailedError{ResourceID: "database", Reason: "quota exceeded"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr, recorder := newEventsReconciler(&fakeRuntime{instance: &unstructured.Unstructured{Object: map[string]interface{}{}}}, newSkippedResources())
+			igr.state.State = tt.state
+			igr.state.ReconcileErr = tt.reconcileErr
+
+			igr.recordReconciliationEvents(tt.previousState)
+			assert.Equal(t, tt.wantEvents, drainEvents(recorder))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/requeue"
+	"github.com/awslabs/kro/pkg/runtime"
+)
+
+// MissingPermissionsError is returned when the identity reconciling an instance
+// isn't allowed to manage all the resources of the graph.
+type MissingPermissionsError struct {
+	Permissions []kroclient.Permission
+}
+
+func (e *MissingPermissionsError) Error() string {
+	permissions := make([]string, 0, len(e.Permissions))
+	for _, permission := range e.Permissions {
+		permissions = append(permissions, permission.String())
+	}
+	return fmt.Sprintf("missing permissions: %s", strings.Join(permissions, ", "))
+}
+
+// checkPermissions verifies, with SelfSubjectAccessReviews, that the execution
+// identity is allowed to perform the given verbs on every resolved resource of
+// the graph. Missing permissions are reported all at once, and the instance is
+// requeued until they're granted. The namespace of resources waiting on their
+// dependencies isn't known yet, they're verified with checkResourcePermissions
+// once resolved.
+func (igr *instanceGraphReconciler) checkPermissions(ctx context.Context, verbs ...string) error {
+	var permissions []kroclient.Permission
+	for _, resourceID := range igr.runtime.TopologicalOrder() {
+		if _, state := igr.runtime.GetResource(resourceID); state != runtime.ResourceStateResolved {
+			continue
+		}
+		permissions = append(permissions, igr.resourcePermissions(resourceID, verbs)...)
+	}
+	return igr.verifyPermissions(ctx, permissions)
+}
+
+// checkResourcePermissions verifies that the execution identity is allowed to
+// perform the given verbs on a resolved resource. Permissions already verified
+// by checkPermissions are cached, and aren't reviewed again.
+func (igr *instanceGraphReconciler) checkResourcePermissions(ctx context.Context, resourceID string, verbs ...string) error {
+	return igr.verifyPermissions(ctx, igr.resourcePermissions(resourceID, verbs))
+}
+
+func (igr *instanceGraphReconciler) verifyPermissions(ctx context.Context, permissions []kroclient.Permission) error {
+	missing, err := igr.clientSet.MissingPermissions(ctx, permissions)
+	if err != nil {
+		return fmt.Errorf("failed to verify permissions: %w", err)
+	}
+	if len(missing) > 0 {
+		return requeue.NeededAfter(&MissingPermissionsError{Permissions: missing}, requeue.DefaultRequeueAfterDuration)
+	}
+	return nil
+}
+
+// resourcePermissions returns the permissions needed to perform the given verbs
+// on a resolved resource, in the namespace it is created in.
+func (igr *instanceGraphReconciler) resourcePermissions(resourceID string, verbs []string) []kroclient.Permission {
+	descriptor := igr.runtime.ResourceDescriptor(resourceID)
+	gvr := descriptor.GetGroupVersionResource()
+
+	var namespace string
+	if descriptor.IsNamespaced() {
+		namespace = igr.getResourceNamespace(resourceID)
+	}
+
+	permissions := make([]kroclient.Permission, 0, len(verbs))
+	for _, verb := range verbs {
+		permissions = append(permissions, kroclient.Permission{
+			Verb:      verb,
+			Group:     gvr.Group,
+			Resource:  gvr.Resource,
+			Namespace: namespace,
+		})
+	}
+	return permissions
+}
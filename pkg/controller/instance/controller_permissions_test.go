@@ -0,0 +1,192 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/requeue"
+)
+
+// accessReviewServer answers SelfSubjectAccessReviews, denying the given
+// permissions, and records the reviewed ones.
+type accessReviewServer struct {
+	mu       sync.Mutex
+	denied   []kroclient.Permission
+	reviewed []kroclient.Permission
+}
+
+func (s *accessReviewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review authorizationv1.SelfSubjectAccessReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	attributes := review.Spec.ResourceAttributes
+	permission := kroclient.Permission{
+		Verb:      attributes.Verb,
+		Group:     attributes.Group,
+		Resource:  attributes.Resource,
+		Namespace: attributes.Namespace,
+	}
+
+	s.mu.Lock()
+	s.reviewed = append(s.reviewed, permission)
+	review.Status.Allowed = true
+	for _, denied := range s.denied {
+		if denied == permission {
+			review.Status.Allowed = false
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+func newPermissionsReconciler(t *testing.T, rt *fakeRuntime, denied ...kroclient.Permission) (*instanceGraphReconciler, *accessReviewServer) {
+	reviews := &accessReviewServer{denied: denied}
+	server := httptest.NewServer(reviews)
+	t.Cleanup(server.Close)
+
+	clientSet, err := kroclient.NewSet(kroclient.Config{RestConfig: &rest.Config{Host: server.URL}})
+	require.NoError(t, err)
+
+	return &instanceGraphReconciler{
+		log:       logr.Discard(),
+		clientSet: clientSet,
+		runtime:   rt,
+	}, reviews
+}
+
+func newPermissionsRuntime() *fakeRuntime {
+	return &fakeRuntime{
+		instance: newObject("my-app", "team-a"),
+		order:    []string{"namespace", "configmap", "deployment", "secret"},
+		descriptors: map[string]*fakeDescriptor{
+			"namespace":  {gvr: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}},
+			"configmap":  {gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespaced: true},
+			"deployment": {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespaced: true},
+			"secret":     {gvr: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespaced: true},
+		},
+		resources: map[string]*unstructured.Unstructured{
+			"namespace": newObject("team-b", ""),
+			// A resource created in another namespace than the instance.
+			"configmap": newObject("config", "team-b"),
+			// A resource created in the namespace of the instance.
+			"deployment": newObject("app", ""),
+			// The secret is waiting on its dependencies, its namespace
+			// isn't known yet.
+		},
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	tests := []struct {
+		name         string
+		denied       []kroclient.Permission
+		wantReviewed []kroclient.Permission
+		wantMissing  []kroclient.Permission
+	}{
+		{
+			name: "resolved resources are checked in their namespace",
+			wantReviewed: []kroclient.Permission{
+				{Verb: "get", Resource: "namespaces"},
+				{Verb: "create", Resource: "namespaces"},
+				{Verb: "get", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "create", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+				{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+			},
+		},
+		{
+			name: "missing permissions are all reported",
+			denied: []kroclient.Permission{
+				{Verb: "create", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+			},
+			wantReviewed: []kroclient.Permission{
+				{Verb: "get", Resource: "namespaces"},
+				{Verb: "create", Resource: "namespaces"},
+				{Verb: "get", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "create", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+				{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+			},
+			wantMissing: []kroclient.Permission{
+				{Verb: "create", Resource: "configmaps", Namespace: "team-b"},
+				{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "team-a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr, reviews := newPermissionsReconciler(t, newPermissionsRuntime(), tt.denied...)
+
+			err := igr.checkPermissions(context.Background(), "get", "create")
+			assert.Equal(t, tt.wantReviewed, reviews.reviewed)
+			if len(tt.wantMissing) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var requeueErr *requeue.RequeueNeededAfter
+			require.True(t, errors.As(err, &requeueErr))
+			var missingErr *MissingPermissionsError
+			require.True(t, errors.As(err, &missingErr))
+			assert.Equal(t, tt.wantMissing, missingErr.Permissions)
+		})
+	}
+}
+
+func TestCheckResourcePermissions(t *testing.T) {
+	rt := newPermissionsRuntime()
+	denied := kroclient.Permission{Verb: "create", Resource: "secrets", Namespace: "team-c"}
+	igr, reviews := newPermissionsReconciler(t, rt, denied)
+
+	require.NoError(t, igr.checkPermissions(context.Background(), "get", "create"))
+	reviews.reviewed = nil
+
+	// Permissions verified upfront are cached.
+	require.NoError(t, igr.checkResourcePermissions(context.Background(), "configmap", "get", "create"))
+	assert.Empty(t, reviews.reviewed)
+
+	// The secret is verified in the namespace it is resolved in.
+	rt.resources["secret"] = newObject("credentials", "team-c")
+	err := igr.checkResourcePermissions(context.Background(), "secret", "get", "create")
+	assert.Equal(t, []kroclient.Permission{
+		{Verb: "get", Resource: "secrets", Namespace: "team-c"},
+		denied,
+	}, reviews.reviewed)
+
+	var missingErr *MissingPermissionsError
+	require.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, []kroclient.Permission{denied}, missingErr.Permissions)
+}
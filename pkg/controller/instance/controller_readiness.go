@@ -0,0 +1,167 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/awslabs/kro/pkg/requeue"
+)
+
+// defaultReadinessMaxInterval is the default maximum interval between two
+// readiness checks of a resource.
+const defaultReadinessMaxInterval = 5 * time.Minute
+
+// readinessPolicy is the readiness policy of a resource, with the defaults
+// applied.
+type readinessPolicy struct {
+	initialDelay  time.Duration
+	maxInterval   time.Duration
+	backoffFactor int32
+	// timeout is zero when the resource is waited for indefinitely.
+	timeout time.Duration
+	// readyTimeout is the ready timeout of the resource, zero when the
+	// resource never fails for not being ready.
+	readyTimeout time.Duration
+}
+
+// readinessPolicy returns the readiness policy of a resource, along with its
+// ready timeout. The initial delay defaults to the default requeue duration
+// of the controller, and the interval stays constant unless a backoff factor
+// is set.
+func (igr *instanceGraphReconciler) readinessPolicy(resourceID string) readinessPolicy {
+	descriptor := igr.runtime.ResourceDescriptor(resourceID)
+	policy := readinessPolicy{
+		initialDelay:  igr.reconcileConfig.DefaultRequeueDuration,
+		maxInterval:   defaultReadinessMaxInterval,
+		backoffFactor: 1,
+		readyTimeout:  descriptor.GetReadyTimeout(),
+	}
+
+	spec := descriptor.GetReadinessPolicy()
+	if spec == nil {
+		return policy
+	}
+	if spec.InitialDelay != nil {
+		policy.initialDelay = spec.InitialDelay.Duration
+	}
+	if spec.MaxInterval != nil {
+		policy.maxInterval = spec.MaxInterval.Duration
+	}
+	if spec.BackoffFactor != nil {
+		policy.backoffFactor = *spec.BackoffFactor
+	}
+	if spec.Timeout != nil {
+		policy.timeout = spec.Timeout.Duration
+	}
+	if policy.maxInterval < policy.initialDelay {
+		policy.maxInterval = policy.initialDelay
+	}
+	return policy
+}
+
+// interval returns the interval before the next readiness check of a resource
+// that has been waited for the given time.
+//
+// Checking the readiness at intervals growing by the backoff factor f, starting
+// at the initial delay d, the next interval after waiting for t is d + t*(f-1).
+// It only depends on the time the resource has been waited for, so it doesn't
+// need to be tracked between reconciliations.
+func (p readinessPolicy) interval(waited time.Duration) time.Duration {
+	interval := p.initialDelay + waited*time.Duration(p.backoffFactor-1)
+	if interval > p.maxInterval || interval < 0 {
+		return p.maxInterval
+	}
+	return interval
+}
+
+// timedOut returns true if a resource that has been waited for the given time
+// exceeded its readiness timeout.
+func (p readinessPolicy) timedOut(waited time.Duration) bool {
+	return p.timeout > 0 && waited > p.timeout
+}
+
+// failed returns true if a resource that has been waited for the given time
+// exceeded its ready timeout.
+func (p readinessPolicy) failed(waited time.Duration) bool {
+	return p.readyTimeout > 0 && waited > p.readyTimeout
+}
+
+// ResourceFailedError is returned when a resource of the instance failed,
+// either because one of its failWhen expressions evaluated to true, or because
+// it wasn't ready before its ready timeout.
+type ResourceFailedError struct {
+	ResourceID string
+	Reason     string
+}
+
+func (e *ResourceFailedError) Error() string {
+	return fmt.Sprintf("resource %s failed: %s", e.ResourceID, e.Reason)
+}
+
+// checkResourceFailed returns a ResourceFailedError if one of the failWhen
+// expressions of the resource evaluates to true. Expressions that can't be
+// evaluated yet, e.g because the status of the resource isn't populated, don't
+// fail the resource.
+func (igr *instanceGraphReconciler) checkResourceFailed(ctx context.Context, resourceID string) *ResourceFailedError {
+	failed, reason, err := igr.runtime.IsResourceFailed(ctx, resourceID)
+	if err != nil {
+		igr.log.V(1).Info("Failed to evaluate failWhen expressions", "resourceID", resourceID, "error", err)
+		return nil
+	}
+	if !failed {
+		return nil
+	}
+	return &ResourceFailedError{ResourceID: resourceID, Reason: reason}
+}
+
+// failResource marks a resource, and the instance, as failed. The instance
+// isn't requeued, failed resources aren't checked again until the spec of the
+// instance changes.
+func (igr *instanceGraphReconciler) failResource(
+	resourceID string,
+	observed *unstructured.Unstructured,
+	resourceState *ResourceState,
+	err *ResourceFailedError,
+) error {
+	resourceState.State = ResourceStateFailed
+	resourceState.Err = err
+	igr.state.State = InstanceStateError
+	igr.recordResourceEvent(corev1.EventTypeWarning, EventReasonResourceFailed, resourceID, observed, "failed: %s", err.Reason)
+	return requeue.None(err)
+}
+
+// hasFailedResource returns true if a resource of the instance failed at the
+// current generation of the instance, as reported by its InstanceSynced
+// condition. Unlike the reconciliations that stopped checking the resource,
+// the status of the instance outlives restarts of the controller.
+func (igr *instanceGraphReconciler) hasFailedResource() bool {
+	instance := igr.runtime.GetInstance()
+	conditions, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "InstanceSynced" {
+			continue
+		}
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		generation, _, _ := unstructured.NestedInt64(condition, "observedGeneration")
+		return reason == "ResourceFailed" && generation == instance.GetGeneration()
+	}
+	return false
+}
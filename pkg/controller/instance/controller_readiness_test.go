@@ -0,0 +1,312 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/requeue"
+)
+
+func TestReadinessPolicy(t *testing.T) {
+	backoffFactor := int32(2)
+
+	tests := []struct {
+		name         string
+		policy       *v1alpha1.ReadinessPolicy
+		readyTimeout time.Duration
+		want         readinessPolicy
+	}{
+		{
+			name: "no policy",
+			want: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: defaultReadinessMaxInterval, backoffFactor: 1},
+		},
+		{
+			name: "full policy",
+			policy: &v1alpha1.ReadinessPolicy{
+				InitialDelay:  &metav1.Duration{Duration: 10 * time.Second},
+				MaxInterval:   &metav1.Duration{Duration: time.Minute},
+				BackoffFactor: &backoffFactor,
+				Timeout:       &metav1.Duration{Duration: time.Hour},
+			},
+			readyTimeout: 2 * time.Hour,
+			want: readinessPolicy{
+				initialDelay:  10 * time.Second,
+				maxInterval:   time.Minute,
+				backoffFactor: 2,
+				timeout:       time.Hour,
+				readyTimeout:  2 * time.Hour,
+			},
+		},
+		{
+			name:         "ready timeout without a policy",
+			readyTimeout: time.Hour,
+			want: readinessPolicy{
+				initialDelay:  3 * time.Second,
+				maxInterval:   defaultReadinessMaxInterval,
+				backoffFactor: 1,
+				readyTimeout:  time.Hour,
+			},
+		},
+		{
+			name: "initial delay above the default max interval",
+			policy: &v1alpha1.ReadinessPolicy{
+				InitialDelay: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			want: readinessPolicy{initialDelay: 10 * time.Minute, maxInterval: 10 * time.Minute, backoffFactor: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				reconcileConfig: ReconcileConfig{DefaultRequeueDuration: 3 * time.Second},
+				runtime: &fakeRuntime{descriptors: map[string]*fakeDescriptor{
+					"cluster": {readinessPolicy: tt.policy, readyTimeout: tt.readyTimeout},
+				}},
+			}
+			assert.Equal(t, tt.want, igr.readinessPolicy("cluster"))
+		})
+	}
+}
+
+func TestReadinessPolicyInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy readinessPolicy
+		waited time.Duration
+		want   time.Duration
+	}{
+		{
+			name:   "constant interval",
+			policy: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: time.Minute, backoffFactor: 1},
+			waited: time.Hour,
+			want:   3 * time.Second,
+		},
+		{
+			name:   "first check",
+			policy: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: time.Minute, backoffFactor: 2},
+			want:   3 * time.Second,
+		},
+		{
+			name:   "growing interval",
+			policy: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: time.Minute, backoffFactor: 3},
+			waited: 6 * time.Second,
+			want:   15 * time.Second,
+		},
+		{
+			name:   "interval capped",
+			policy: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: time.Minute, backoffFactor: 2},
+			waited: time.Hour,
+			want:   time.Minute,
+		},
+		{
+			name:   "overflowing interval",
+			policy: readinessPolicy{initialDelay: 3 * time.Second, maxInterval: time.Minute, backoffFactor: 1000},
+			waited: 24 * 365 * time.Hour,
+			want:   time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.interval(tt.waited))
+		})
+	}
+}
+
+func TestReadinessPolicyTimedOut(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		waited  time.Duration
+		want    bool
+	}{
+		{
+			name:   "no timeout",
+			waited: 24 * time.Hour,
+		},
+		{
+			name:    "within the timeout",
+			timeout: time.Minute,
+			waited:  time.Minute,
+		},
+		{
+			name:    "past the timeout",
+			timeout: time.Minute,
+			waited:  time.Minute + time.Second,
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, readinessPolicy{timeout: tt.timeout}.timedOut(tt.waited))
+		})
+	}
+}
+
+func TestReadinessPolicyFailed(t *testing.T) {
+	tests := []struct {
+		name         string
+		readyTimeout time.Duration
+		waited       time.Duration
+		want         bool
+	}{
+		{
+			name:   "no ready timeout",
+			waited: 24 * time.Hour,
+		},
+		{
+			name:         "within the ready timeout",
+			readyTimeout: time.Minute,
+			waited:       time.Minute,
+		},
+		{
+			name:         "past the ready timeout",
+			readyTimeout: time.Minute,
+			waited:       time.Minute + time.Second,
+			want:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, readinessPolicy{readyTimeout: tt.readyTimeout}.failed(tt.waited))
+		})
+	}
+}
+
+// newFailedInstance returns an instance at the given generation, whose
+// InstanceSynced condition was reported at the observed generation.
+func newFailedInstance(generation, observedGeneration int64, reason string) *unstructured.Unstructured {
+	instance := newObject("my-app", "default")
+	instance.SetGeneration(generation)
+	instance.Object["status"] = map[string]interface{}{
+		"state": InstanceStateError,
+		"conditions": []interface{}{
+			createCondition("InstanceSynced", corev1.ConditionFalse, reason, "resource database failed: quota exceeded", observedGeneration),
+		},
+	}
+	return instance
+}
+
+func TestHasFailedResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *unstructured.Unstructured
+		want     bool
+	}{
+		{
+			name:     "new instance",
+			instance: newObject("my-app", "default"),
+		},
+		{
+			name:     "resource failed at the current generation",
+			instance: newFailedInstance(2, 2, "ResourceFailed"),
+			want:     true,
+		},
+		{
+			name:     "spec changed since the resource failed",
+			instance: newFailedInstance(3, 2, "ResourceFailed"),
+		},
+		{
+			name:     "reconciliation failed",
+			instance: newFailedInstance(2, 2, "ReconciliationFailed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{runtime: &fakeRuntime{instance: tt.instance}}
+			assert.Equal(t, tt.want, igr.hasFailedResource())
+		})
+	}
+}
+
+func TestReconcileInstanceWithFailedResource(t *testing.T) {
+	resourceGroup := "reconcile-failed-resource"
+	defer instances.forgetResourceGroup(resourceGroup)
+
+	// The reconciler has no clients, the instance must be left untouched.
+	igr := &instanceGraphReconciler{
+		log:           logr.Discard(),
+		runtime:       &fakeRuntime{instance: newFailedInstance(2, 2, "ResourceFailed")},
+		resourceGroup: resourceGroup,
+	}
+
+	// The instance isn't requeued, e.g after a restart of the controller.
+	require.NoError(t, igr.reconcile(context.Background()))
+	assert.Equal(t, InstanceStateError, igr.state.State)
+	assert.Equal(t, 1.0, testutil.ToFloat64(instanceCount.WithLabelValues(resourceGroup, InstanceStateError)))
+}
+
+func TestDelayedRequeue(t *testing.T) {
+	tests := []struct {
+		name             string
+		resourceStates   map[string]*ResourceState
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "no pending resource",
+			resourceStates:   map[string]*ResourceState{"configmap": {State: "SYNCED"}},
+			wantRequeueAfter: 3 * time.Second,
+		},
+		{
+			name: "smallest pending interval",
+			resourceStates: map[string]*ResourceState{
+				"configmap": {State: "SYNCED"},
+				"cluster":   {State: "WAITING_FOR_READINESS", RequeueAfter: time.Minute},
+				"database":  {State: "WAITING_FOR_READINESS", RequeueAfter: 10 * time.Second},
+				"nodegroup": {State: "CREATED", RequeueAfter: 30 * time.Second},
+			},
+			wantRequeueAfter: 10 * time.Second,
+		},
+		{
+			name: "pending interval above the default",
+			resourceStates: map[string]*ResourceState{
+				"cluster": {State: "WAITING_FOR_READINESS", RequeueAfter: time.Minute},
+			},
+			wantRequeueAfter: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igr := &instanceGraphReconciler{
+				reconcileConfig: ReconcileConfig{DefaultRequeueDuration: 3 * time.Second},
+				state:           newInstanceState(),
+			}
+			igr.state.ResourceStates = tt.resourceStates
+
+			err := igr.delayedRequeue(errors.New("resource not ready"))
+			var requeueErr *requeue.RequeueNeededAfter
+			require.True(t, errors.As(err, &requeueErr))
+			assert.Equal(t, tt.wantRequeueAfter, requeueErr.Duration())
+			assert.EqualError(t, requeueErr.Unwrap(), "resource not ready")
+		})
+	}
+}
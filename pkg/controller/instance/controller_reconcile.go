@@ -15,16 +15,23 @@ package instance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 
+	krocel "github.com/awslabs/kro/pkg/cel"
+	kroclient "github.com/awslabs/kro/pkg/client"
 	"github.com/awslabs/kro/pkg/metadata"
 	"github.com/awslabs/kro/pkg/requeue"
 	"github.com/awslabs/kro/pkg/runtime"
@@ -40,6 +47,9 @@ type instanceGraphReconciler struct {
 	gvr schema.GroupVersionResource
 	// client is a dynamic client for interacting with the Kubernetes API server
 	client dynamic.Interface
+	// clientSet is the client set of the identity used by client. It is used to
+	// verify the identity is allowed to manage the resources of the graph.
+	clientSet *kroclient.Set
 	// runtime is the runtime representation of the ResourceGroup. It holds the
 	// information about the instance and its sub-resources, the CEL expressions
 	// their dependencies, and the resolved values... etc
@@ -54,6 +64,14 @@ type instanceGraphReconciler struct {
 	reconcileConfig ReconcileConfig
 	// state holds the current state of the instance and its sub-resources.
 	state *InstanceState
+	// recorder records events on the instance.
+	recorder record.EventRecorder
+	// resourceGroup is the name of the ResourceGroup, used to label the
+	// instance metrics.
+	resourceGroup string
+	// skippedResources tracks the resources skipped by the instances of the
+	// ResourceGroup.
+	skippedResources *skippedResources
 }
 
 // reconcile performs the reconciliation of the instance and its sub-resources.
@@ -63,21 +81,67 @@ func (igr *instanceGraphReconciler) reconcile(ctx context.Context) error {
 	instance := igr.runtime.GetInstance()
 	igr.state = newInstanceState()
 
+	// Paused instances are left untouched, deletions included, until they're
+	// resumed.
+	if reason, message, paused := igr.pausedBy(); paused {
+		return igr.handlePause(ctx, reason, message)
+	}
+
 	// Handle instance deletion if marked for deletion
 	if !instance.GetDeletionTimestamp().IsZero() {
 		igr.state.State = "DELETING"
 		return igr.handleReconciliation(ctx, igr.handleInstanceDeletion)
 	}
 
-	return igr.handleReconciliation(ctx, igr.reconcileInstance)
+	// Failed resources aren't checked again until the spec of the instance
+	// changes, including when the instance is queued again by a restart of
+	// the controller.
+	if igr.hasFailedResource() {
+		igr.log.V(1).Info("Skipping reconciliation of instance with a failed resource")
+		igr.state.State = InstanceStateError
+		igr.recordInstanceState(InstanceStateError)
+		return nil
+	}
+
+	if err := igr.handleReconciliation(ctx, igr.reconcileInstance); err != nil {
+		return err
+	}
+
+	// Reconcile active instances again later, e.g to correct the drift of
+	// their resources.
+	if igr.state.State == InstanceStateActive {
+		if interval := igr.reconcileInterval(); interval > 0 {
+			return requeue.NeededAfter(nil, wait.Jitter(interval, reconcileIntervalJitter))
+		}
+	}
+	return nil
+}
+
+// reconcileInterval returns the interval at which the instance is reconciled
+// again once active. The reconcile interval annotation of the instance takes
+// precedence over the interval of the ResourceGroup.
+func (igr *instanceGraphReconciler) reconcileInterval() time.Duration {
+	value, ok := igr.runtime.GetInstance().GetAnnotations()[metadata.ReconcileIntervalAnnotation]
+	if !ok {
+		return igr.reconcileConfig.ReconcileInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		igr.log.Info("Ignoring invalid reconcile interval annotation", "value", value)
+		return igr.reconcileConfig.ReconcileInterval
+	}
+	return interval
 }
 
 // handleReconciliation provides a common wrapper for reconciliation operations,
 // handling status updates and error management.
 func (igr *instanceGraphReconciler) handleReconciliation(ctx context.Context, reconcileFunc func(context.Context) error) error {
+	previousState, _, _ := unstructured.NestedString(igr.runtime.GetInstance().Object, "status", "state")
 	defer func() {
 		// Update instance state based on reconciliation result
 		igr.updateInstanceState()
+		igr.recordReconciliationEvents(previousState)
+		igr.recordInstanceState(previousState)
 
 		// Prepare and patch status
 		status := igr.prepareStatus()
@@ -107,6 +171,11 @@ func (igr *instanceGraphReconciler) reconcileInstance(ctx context.Context) error
 		igr.state.ResourceStates[resourceID] = &ResourceState{State: "PENDING"}
 	}
 
+	// Make sure all the resources can be managed before touching any of them
+	if err := igr.checkPermissions(ctx, "get", "create"); err != nil {
+		return err
+	}
+
 	// Reconcile resources in topological order
 	for _, resourceID := range igr.runtime.TopologicalOrder() {
 		if err := igr.reconcileResource(ctx, resourceID); err != nil {
@@ -114,7 +183,7 @@ func (igr *instanceGraphReconciler) reconcileInstance(ctx context.Context) error
 		}
 
 		// Synchronize runtime state after each resource
-		if _, err := igr.runtime.Synchronize(); err != nil {
+		if _, err := igr.runtime.Synchronize(ctx); err != nil {
 			return fmt.Errorf("failed to synchronize reconciling resource %s: %w", resourceID, err)
 		}
 	}
@@ -142,12 +211,34 @@ func (igr *instanceGraphReconciler) reconcileResource(ctx context.Context, resou
 	igr.state.ResourceStates[resourceID] = resourceState
 
 	// Check if resource should be created
-	if want, err := igr.runtime.WantToCreateResource(resourceID); err != nil || !want {
+	want, err := igr.runtime.WantToCreateResource(ctx, resourceID)
+	if krocel.IsLimitError(err) {
+		resourceState.State = "ERROR"
+		resourceState.Err = fmt.Errorf("failed to evaluate includeWhen expressions: %w", err)
+		return resourceState.Err
+	}
+	var evalErr *runtime.EvalError
+	if errors.As(err, &evalErr) && evalErr.IsIncompleteData {
+		return igr.delayedRequeue(fmt.Errorf("failed to evaluate includeWhen expressions: %w", err))
+	}
+	if err != nil || !want {
 		log.V(1).Info("Skipping resource creation", "reason", err)
+		// Resources are skipped at every reconciliation, only record the
+		// ones that weren't skipped by the previous one.
+		if igr.skippedResources.skip(igr.instanceKey(), resourceID) {
+			if err != nil {
+				igr.recordResourceEvent(corev1.EventTypeWarning, EventReasonResourceSkipped, resourceID, nil,
+					"skipped, includeWhen expressions failed: %v", err)
+			} else {
+				igr.recordResourceEvent(corev1.EventTypeNormal, EventReasonResourceSkipped, resourceID, nil,
+					"skipped, includeWhen expressions are false")
+			}
+		}
 		resourceState.State = "SKIPPED"
 		igr.runtime.IgnoreResource(resourceID)
 		return nil
 	}
+	igr.skippedResources.include(igr.instanceKey(), resourceID)
 
 	// Get and validate resource state
 	resource, state := igr.runtime.GetResource(resourceID)
@@ -155,6 +246,11 @@ func (igr *instanceGraphReconciler) reconcileResource(ctx context.Context, resou
 		return igr.delayedRequeue(fmt.Errorf("resource %s not resolved: state=%v", resourceID, state))
 	}
 
+	// Resources resolved during this reconciliation weren't verified upfront
+	if err := igr.checkResourcePermissions(ctx, resourceID, "get", "create"); err != nil {
+		return err
+	}
+
 	// Handle resource reconciliation
 	return igr.handleResourceReconciliation(ctx, resourceID, resource, resourceState)
 }
@@ -173,7 +269,9 @@ func (igr *instanceGraphReconciler) handleResourceReconciliation(
 	rc := igr.getResourceClient(resourceID)
 
 	// Check if resource exists
-	observed, err := rc.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	getCtx, span := igr.startResourceSpan(ctx, "get", resourceID, resource)
+	observed, err := rc.Get(getCtx, resource.GetName(), metav1.GetOptions{})
+	endResourceSpan(span, err)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return igr.handleResourceCreation(ctx, rc, resource, resourceID, resourceState)
@@ -186,14 +284,43 @@ func (igr *instanceGraphReconciler) handleResourceReconciliation(
 	// Update runtime with observed state
 	igr.runtime.SetResource(resourceID, observed)
 
+	// Check whether the resource failed
+	if failedErr := igr.checkResourceFailed(ctx, resourceID); failedErr != nil {
+		return igr.failResource(resourceID, observed, resourceState, failedErr)
+	}
+
 	// Check resource readiness
-	if ready, reason, err := igr.runtime.IsResourceReady(resourceID); err != nil || !ready {
+	if ready, reason, err := igr.runtime.IsResourceReady(ctx, resourceID); err != nil || !ready {
 		log.V(1).Info("Resource not ready", "reason", reason, "error", err)
+		instances.resourcePending(igr.resourceGroup, igr.instanceKey(), resourceID)
+		// Resources aren't updated once created, they are waited for since
+		// their creation, which persists across restarts of the controller.
+		policy := igr.readinessPolicy(resourceID)
+		waited := time.Since(observed.GetCreationTimestamp().Time)
+		if policy.failed(waited) {
+			if reason == "" && err != nil {
+				reason = err.Error()
+			}
+			return igr.failResource(resourceID, observed, resourceState, &ResourceFailedError{
+				ResourceID: resourceID,
+				Reason:     fmt.Sprintf("not ready after %s: %s", policy.readyTimeout, reason),
+			})
+		}
 		resourceState.State = "WAITING_FOR_READINESS"
 		resourceState.Err = fmt.Errorf("resource not ready: %s: %w", reason, err)
+		resourceState.RequeueAfter = policy.interval(waited)
+		if policy.timedOut(waited) {
+			// Keep checking the readiness of the resource, it may still
+			// become ready.
+			resourceState.State = ResourceStateReadinessTimeout
+			resourceState.Err = fmt.Errorf("resource not ready after %s: %s: %w", policy.timeout, reason, err)
+			igr.state.State = InstanceStateDegraded
+		}
 		return igr.delayedRequeue(resourceState.Err)
 	}
 
+	instances.resourceReady(igr.resourceGroup, igr.instanceKey(), resourceID, observed.GetCreationTimestamp().Time)
+
 	resourceState.State = "SYNCED"
 	return igr.updateResource(ctx, rc, resource, observed, resourceID, resourceState)
 }
@@ -222,13 +349,22 @@ func (igr *instanceGraphReconciler) handleResourceCreation(
 
 	// Apply labels and create resource
 	igr.instanceSubResourcesLabeler.ApplyLabels(resource)
-	if _, err := rc.Create(ctx, resource, metav1.CreateOptions{}); err != nil {
+	createCtx, span := igr.startResourceSpan(ctx, "create", resourceID, resource)
+	_, err := rc.Create(createCtx, resource, metav1.CreateOptions{})
+	endResourceSpan(span, err)
+	recordResourceOperation(resource.GroupVersionKind(), resourceOperationCreate, err)
+	if err != nil {
 		resourceState.State = "ERROR"
 		resourceState.Err = fmt.Errorf("failed to create resource: %w", err)
+		igr.recordResourceEvent(corev1.EventTypeWarning, EventReasonResourceCreationFailed, resourceID, resource,
+			"%v", resourceState.Err)
 		return resourceState.Err
 	}
+	igr.recordResourceEvent(corev1.EventTypeNormal, EventReasonResourceCreated, resourceID, resource, "created")
+	instances.resourcePending(igr.resourceGroup, igr.instanceKey(), resourceID)
 
 	resourceState.State = "CREATED"
+	resourceState.RequeueAfter = igr.readinessPolicy(resourceID).initialDelay
 	return igr.delayedRequeue(fmt.Errorf("awaiting resource creation completion"))
 }
 
@@ -256,9 +392,14 @@ func (igr *instanceGraphReconciler) updateResource(
 func (igr *instanceGraphReconciler) handleInstanceDeletion(ctx context.Context) error {
 	igr.log.V(1).Info("Beginning instance deletion process")
 
+	// Make sure all the resources can be deleted before deleting any of them
+	if err := igr.checkPermissions(ctx, "get", "delete"); err != nil {
+		return err
+	}
+
 	// Initialize deletion state for all resources
-	if err := igr.initializeDeletionState(); err != nil {
-		return fmt.Errorf("failed to initialize deletion state: %w", err)
+	if err := igr.initializeDeletionState(ctx); err != nil {
+		return err
 	}
 
 	// Delete resources in reverse order
@@ -272,9 +413,9 @@ func (igr *instanceGraphReconciler) handleInstanceDeletion(ctx context.Context)
 
 // initializeDeletionState prepares resources for deletion by checking their
 // current state and marking them appropriately.
-func (igr *instanceGraphReconciler) initializeDeletionState() error {
+func (igr *instanceGraphReconciler) initializeDeletionState(ctx context.Context) error {
 	for _, resourceID := range igr.runtime.TopologicalOrder() {
-		if _, err := igr.runtime.Synchronize(); err != nil {
+		if _, err := igr.runtime.Synchronize(ctx); err != nil {
 			return fmt.Errorf("failed to synchronize during deletion state initialization: %w", err)
 		}
 
@@ -286,9 +427,17 @@ func (igr *instanceGraphReconciler) initializeDeletionState() error {
 			continue
 		}
 
+		// Resources resolved from the observed state of their dependencies
+		// weren't verified upfront
+		if err := igr.checkResourcePermissions(ctx, resourceID, "get", "delete"); err != nil {
+			return err
+		}
+
 		// Check if resource exists
 		rc := igr.getResourceClient(resourceID)
-		observed, err := rc.Get(context.TODO(), resource.GetName(), metav1.GetOptions{})
+		getCtx, span := igr.startResourceSpan(ctx, "get", resourceID, resource)
+		observed, err := rc.Get(getCtx, resource.GetName(), metav1.GetOptions{})
+		endResourceSpan(span, err)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				igr.state.ResourceStates[resourceID] = &ResourceState{
@@ -335,7 +484,12 @@ func (igr *instanceGraphReconciler) deleteResource(ctx context.Context, resource
 	rc := igr.getResourceClient(resourceID)
 
 	// Attempt to delete the resource
-	err := rc.Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
+	deleteCtx, span := igr.startResourceSpan(ctx, "delete", resourceID, resource)
+	err := rc.Delete(deleteCtx, resource.GetName(), metav1.DeleteOptions{})
+	endResourceSpan(span, err)
+	if !apierrors.IsNotFound(err) {
+		recordResourceOperation(resource.GroupVersionKind(), resourceOperationDelete, err)
+	}
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			igr.state.ResourceStates[resourceID].State = "DELETED"
@@ -343,8 +497,11 @@ func (igr *instanceGraphReconciler) deleteResource(ctx context.Context, resource
 		}
 		igr.state.ResourceStates[resourceID].State = InstanceStateError
 		igr.state.ResourceStates[resourceID].Err = fmt.Errorf("failed to delete resource: %w", err)
+		igr.recordResourceEvent(corev1.EventTypeWarning, EventReasonResourceDeletionFailed, resourceID, resource,
+			"%v", igr.state.ResourceStates[resourceID].Err)
 		return igr.state.ResourceStates[resourceID].Err
 	}
+	igr.recordResourceEvent(corev1.EventTypeNormal, EventReasonResourceDeleted, resourceID, resource, "deleted")
 
 	igr.state.ResourceStates[resourceID].State = InstanceStateDeleting
 	return igr.delayedRequeue(fmt.Errorf("resource deletion in progress"))
@@ -419,9 +576,34 @@ func (igr *instanceGraphReconciler) setUnmanaged(ctx context.Context, obj *unstr
 	return updated, nil
 }
 
+// instanceKey returns the namespace/name key of the instance, used to track
+// the instance metrics.
+func (igr *instanceGraphReconciler) instanceKey() string {
+	instance := igr.runtime.GetInstance()
+	return instance.GetNamespace() + "/" + instance.GetName()
+}
+
+// recordInstanceState records the state the instance ends the reconciliation
+// in, for the instance metrics.
+func (igr *instanceGraphReconciler) recordInstanceState(previousState string) {
+	instances.setState(igr.resourceGroup, igr.instanceKey(), previousState, igr.state.State,
+		igr.runtime.GetInstance().GetCreationTimestamp().Time)
+}
+
 // delayedRequeue wraps an error with requeue information for the controller runtime.
+// The instance is requeued after the smallest interval requested by its pending
+// resources, or after the default requeue duration if none did.
 func (igr *instanceGraphReconciler) delayedRequeue(err error) error {
-	return requeue.NeededAfter(err, igr.reconcileConfig.DefaultRequeueDuration)
+	requeueAfter := time.Duration(0)
+	for _, resourceState := range igr.state.ResourceStates {
+		if resourceState.RequeueAfter > 0 && (requeueAfter == 0 || resourceState.RequeueAfter < requeueAfter) {
+			requeueAfter = resourceState.RequeueAfter
+		}
+	}
+	if requeueAfter == 0 {
+		requeueAfter = igr.reconcileConfig.DefaultRequeueDuration
+	}
+	return requeue.NeededAfter(err, requeueAfter)
 }
 
 // getResourceNamespace determines the appropriate namespace for a resource.
@@ -15,13 +15,19 @@ package instance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/awslabs/kro/api/v1alpha1"
+	krocel "github.com/awslabs/kro/pkg/cel"
+	"github.com/awslabs/kro/pkg/metadata"
 	"github.com/awslabs/kro/pkg/requeue"
 )
 
@@ -74,10 +80,28 @@ func (igr *instanceGraphReconciler) prepareConditions(
 
 	// Add primary reconciliation condition
 	if reconcileErr != nil {
+		reason := "ReconciliationFailed"
+		if krocel.IsLimitError(reconcileErr) {
+			// Expressions exceeding their limits will keep failing until the
+			// ResourceGroup or the instance spec changes, make it obvious.
+			reason = "CELLimitExceeded"
+		}
+		var missingPermissionsErr *MissingPermissionsError
+		if errors.As(reconcileErr, &missingPermissionsErr) {
+			// List the missing permissions, so they can be granted to the
+			// identity reconciling the instance.
+			reason = "MissingPermissions"
+		}
+		var resourceFailedErr *ResourceFailedError
+		if errors.As(reconcileErr, &resourceFailedErr) {
+			// The failed resource isn't checked again until the spec of
+			// the instance changes.
+			reason = "ResourceFailed"
+		}
 		conditions = append(conditions, createCondition(
 			"InstanceSynced",
 			corev1.ConditionFalse,
-			"ReconciliationFailed",
+			reason,
 			reconcileErr.Error(),
 			generation,
 		))
@@ -91,9 +115,73 @@ func (igr *instanceGraphReconciler) prepareConditions(
 		))
 	}
 
+	// Report the resources that exceeded their readiness timeout
+	if igr.state.State == InstanceStateDegraded {
+		var timedOut []string
+		for resourceID, resourceState := range igr.state.ResourceStates {
+			if resourceState.State == ResourceStateReadinessTimeout {
+				timedOut = append(timedOut, resourceID)
+			}
+		}
+		sort.Strings(timedOut)
+		conditions = append(conditions, createCondition(
+			v1alpha1.InstanceConditionTypeDegraded,
+			corev1.ConditionTrue,
+			"ReadinessTimeout",
+			fmt.Sprintf("Resources exceeded their readiness timeout: %s", strings.Join(timedOut, ", ")),
+			generation,
+		))
+	}
+
 	return conditions
 }
 
+// pausedBy returns the reason and the message of the Paused condition if the
+// instance, or its ResourceGroup, is paused.
+func (igr *instanceGraphReconciler) pausedBy() (string, string, bool) {
+	switch {
+	case metadata.IsPaused(igr.runtime.GetInstance().GetAnnotations()):
+		return "InstancePaused", fmt.Sprintf("Reconciliation is paused by the %s annotation", metadata.PausedAnnotation), true
+	case igr.reconcileConfig.Paused:
+		return "ResourceGroupPaused", fmt.Sprintf("Reconciliation is paused by the %s annotation of the ResourceGroup", metadata.PausedAnnotation), true
+	default:
+		return "", "", false
+	}
+}
+
+// handlePause sets the Paused condition on the instance, leaving the rest of
+// its status and its resources untouched. The condition is dropped by the
+// first reconciliation after the instance is resumed.
+func (igr *instanceGraphReconciler) handlePause(ctx context.Context, reason, message string) error {
+	instance := igr.runtime.GetInstance()
+	status := igr.getResolvedStatus()
+
+	existing, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+	conditions := make([]interface{}, 0, len(existing)+1)
+	for _, c := range existing {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == string(v1alpha1.InstanceConditionTypePaused) {
+			if condition["reason"] == reason {
+				// Already reported as paused.
+				return nil
+			}
+			continue
+		}
+		conditions = append(conditions, c)
+	}
+	conditions = append(conditions, createCondition(
+		v1alpha1.InstanceConditionTypePaused,
+		corev1.ConditionTrue,
+		reason,
+		message,
+		instance.GetGeneration(),
+	))
+	status["conditions"] = conditions
+
+	igr.recordEvent(corev1.EventTypeNormal, EventReasonPaused, "%s", message)
+	return igr.patchInstanceStatus(ctx, status)
+}
+
 // patchInstanceStatus updates the status subresource of the instance.
 func (igr *instanceGraphReconciler) patchInstanceStatus(ctx context.Context, status map[string]interface{}) error {
 	instance := igr.runtime.GetInstance().DeepCopy()
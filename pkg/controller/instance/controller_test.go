@@ -0,0 +1,125 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	kroclient "github.com/awslabs/kro/pkg/client"
+)
+
+// newServiceAccountServer returns an API server serving the given service
+// accounts of the default namespace, and counting the requests it receives.
+func newServiceAccountServer(t *testing.T, serviceAccounts ...string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		for _, sa := range serviceAccounts {
+			if r.URL.Path == "/api/v1/namespaces/default/serviceaccounts/"+sa {
+				_ = json.NewEncoder(w).Encode(&corev1.ServiceAccount{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+					ObjectMeta: metav1.ObjectMeta{Name: sa, Namespace: "default"},
+				})
+				return
+			}
+		}
+		status := apierrors.NewNotFound(schema.GroupResource{Resource: "serviceaccounts"}, "").Status()
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestGetRequestedServiceAccountClient(t *testing.T) {
+	tests := []struct {
+		name            string
+		allowed         []string
+		existing        []string
+		requested       string
+		wantUser        string
+		wantErr         string
+		wantAPIRequests int32
+	}{
+		{
+			name:            "allowed service account",
+			allowed:         []string{"deployer", "reader"},
+			existing:        []string{"deployer"},
+			requested:       "deployer",
+			wantUser:        "system:serviceaccount:default:deployer",
+			wantAPIRequests: 1,
+		},
+		{
+			name:            "service account not in the allowlist",
+			allowed:         []string{"reader"},
+			existing:        []string{"deployer"},
+			requested:       "deployer",
+			wantErr:         "service account deployer is not allowed by the resource group",
+			wantAPIRequests: 0,
+		},
+		{
+			name:            "empty allowlist",
+			existing:        []string{"deployer"},
+			requested:       "deployer",
+			wantErr:         "service account deployer is not allowed by the resource group",
+			wantAPIRequests: 0,
+		},
+		{
+			name:            "allowed service account that does not exist",
+			allowed:         []string{"deployer"},
+			requested:       "deployer",
+			wantErr:         "service account default/deployer not found",
+			wantAPIRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newServiceAccountServer(t, tt.existing...)
+			clientSet, err := kroclient.NewSet(kroclient.Config{RestConfig: &rest.Config{Host: server.URL}})
+			require.NoError(t, err)
+
+			c := &Controller{
+				log:                    logr.Discard(),
+				clientSet:              clientSet,
+				allowedServiceAccounts: tt.allowed,
+			}
+
+			set, err := c.getExecutionClient(context.Background(), "default", tt.requested)
+			assert.Equal(t, tt.wantAPIRequests, requests.Load())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, set)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, set.RESTConfig().Impersonate.UserName)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/awslabs/kro/pkg/requeue"
+	"github.com/awslabs/kro/pkg/tracing"
+)
+
+// startResourceSpan starts a span tracing an operation made on a resource of
+// the instance. The span carries the instance UID, so that the operations of
+// an instance can be looked up together.
+func (igr *instanceGraphReconciler) startResourceSpan(
+	ctx context.Context,
+	operation string,
+	resourceID string,
+	resource *unstructured.Unstructured,
+) (context.Context, trace.Span) {
+	gvr := igr.runtime.ResourceDescriptor(resourceID).GetGroupVersionResource()
+	return tracing.Start(ctx, "resource."+operation,
+		tracing.InstanceUIDKey.String(string(igr.runtime.GetInstance().GetUID())),
+		tracing.ResourceIDKey.String(resourceID),
+		tracing.ResourceGVRKey.String(gvr.String()),
+		tracing.ResourceNameKey.String(resource.GetName()),
+	)
+}
+
+// endResourceSpan ends a span started by startResourceSpan. Resources that
+// are not found are part of the normal lifecycle and aren't reported as
+// errors.
+func endResourceSpan(span trace.Span, err error) {
+	if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+// endReconcileSpan ends the span of an instance reconciliation. Requeues are
+// part of the normal lifecycle, e.g waiting for a resource to become ready,
+// and aren't reported as errors.
+func endReconcileSpan(span trace.Span, err error) {
+	switch err.(type) {
+	case *requeue.NoRequeue, *requeue.RequeueNeeded, *requeue.RequeueNeededAfter:
+		err = nil
+	}
+	tracing.End(span, err)
+}
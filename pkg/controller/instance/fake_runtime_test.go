@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/runtime"
+)
+
+// fakeRuntime is a runtime serving a fixed set of resources. Resources without
+// an object are waiting on their dependencies.
+type fakeRuntime struct {
+	runtime.Interface
+	instance    *unstructured.Unstructured
+	order       []string
+	descriptors map[string]*fakeDescriptor
+	resources   map[string]*unstructured.Unstructured
+	// excluded holds the resources whose includeWhen expressions are false,
+	// or fail with the given error.
+	excluded map[string]error
+	ignored  []string
+}
+
+func (f *fakeRuntime) TopologicalOrder() []string {
+	return f.order
+}
+
+func (f *fakeRuntime) ResourceDescriptor(resourceID string) runtime.ResourceDescriptor {
+	return f.descriptors[resourceID]
+}
+
+func (f *fakeRuntime) GetResource(resourceID string) (*unstructured.Unstructured, runtime.ResourceState) {
+	if resource, ok := f.resources[resourceID]; ok {
+		return resource, runtime.ResourceStateResolved
+	}
+	return nil, runtime.ResourceStateWaitingOnDependencies
+}
+
+func (f *fakeRuntime) GetInstance() *unstructured.Unstructured {
+	return f.instance
+}
+
+func (f *fakeRuntime) WantToCreateResource(_ context.Context, resourceID string) (bool, error) {
+	if err, ok := f.excluded[resourceID]; ok {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *fakeRuntime) IgnoreResource(resourceID string) {
+	f.ignored = append(f.ignored, resourceID)
+}
+
+type fakeDescriptor struct {
+	runtime.ResourceDescriptor
+	gvr             schema.GroupVersionResource
+	namespaced      bool
+	readinessPolicy *v1alpha1.ReadinessPolicy
+	readyTimeout    time.Duration
+}
+
+func (f *fakeDescriptor) GetGroupVersionResource() schema.GroupVersionResource {
+	return f.gvr
+}
+
+func (f *fakeDescriptor) IsNamespaced() bool {
+	return f.namespaced
+}
+
+func (f *fakeDescriptor) GetReadinessPolicy() *v1alpha1.ReadinessPolicy {
+	return f.readinessPolicy
+}
+
+func (f *fakeDescriptor) GetReadyTimeout() time.Duration {
+	return f.readyTimeout
+}
+
+func newObject(name, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
@@ -13,12 +13,26 @@
 
 package instance
 
+import "time"
+
 const (
 	InstanceStateInProgress = "IN_PROGRESS"
 	InstanceStateFailed     = "FAILED"
 	InstanceStateActive     = "ACTIVE"
 	InstanceStateDeleting   = "DELETING"
 	InstanceStateError      = "ERROR"
+	// InstanceStateDegraded is the state of the instances with a resource
+	// that exceeded its readiness timeout.
+	InstanceStateDegraded = "DEGRADED"
+)
+
+const (
+	// ResourceStateReadinessTimeout is the state of a resource that exceeded
+	// its readiness timeout.
+	ResourceStateReadinessTimeout = "READINESS_TIMEOUT"
+	// ResourceStateFailed is the state of a resource that matched one of its
+	// failWhen expressions, or that wasn't ready before its ready timeout.
+	ResourceStateFailed = "FAILED"
 )
 
 // newInstanceState creates a new InstanceState with initialized fields
@@ -36,6 +50,9 @@ type ResourceState struct {
 	State string
 	// Err captures any error associated with the current state
 	Err error
+	// RequeueAfter is the interval after which the resource needs to be
+	// checked again, zero if it doesn't.
+	RequeueAfter time.Duration
 }
 
 // InstanceState tracks the overall state of resources being managed
@@ -14,7 +14,12 @@
 package instance
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -27,8 +32,35 @@ const (
 	MetricImpersonationErrors = "controller_impersonation_errors_total"
 	// MetricImpersonationDuration tracks the duration of impersonation operations
 	MetricImpersonationDuration = "controller_impersonation_duration_seconds"
+	// MetricImpersonationCacheTotal is the total number of impersonated client
+	// lookups in the cache, by result (hit or miss)
+	MetricImpersonationCacheTotal = "controller_impersonation_cache_total"
+	// MetricInstances is the number of instances per resource group and state
+	MetricInstances = "instance_count"
+	// MetricInstanceTimeToReady tracks the duration between the creation of an
+	// instance and the first time it becomes active
+	MetricInstanceTimeToReady = "instance_time_to_ready_seconds"
+	// MetricResourceTimeToReady tracks the duration between the creation of a
+	// resource of an instance and the first time it is observed ready
+	MetricResourceTimeToReady = "instance_resource_time_to_ready_seconds"
+	// MetricResourceOperationsTotal is the total number of operations made on
+	// the resources of the instances
+	MetricResourceOperationsTotal = "instance_resource_operations_total"
+	// MetricResourceOperationErrors is the total number of operations made on
+	// the resources of the instances that failed
+	MetricResourceOperationErrors = "instance_resource_operation_errors_total"
+)
+
+// Operations made on the resources of the instances.
+const (
+	resourceOperationCreate = "create"
+	resourceOperationDelete = "delete"
 )
 
+// timeToReadyBuckets range from a second to an hour, resources backed by
+// cloud providers can take a while to become ready.
+var timeToReadyBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
 var (
 	impersonationTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -54,16 +86,203 @@ var (
 		},
 		[]string{"namespace", "service_account"},
 	)
+
+	impersonationCacheTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricImpersonationCacheTotal,
+			Help: "Total number of impersonated client cache lookups by namespace, service account and result",
+		},
+		[]string{"namespace", "service_account", "result"},
+	)
+
+	instanceCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricInstances,
+			Help: "Number of instances by resource group and state",
+		},
+		[]string{"resource_group", "state"},
+	)
+
+	instanceTimeToReady = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    MetricInstanceTimeToReady,
+			Help:    "Duration between the creation of an instance and the first time it becomes active",
+			Buckets: timeToReadyBuckets,
+		},
+		[]string{"resource_group"},
+	)
+
+	resourceTimeToReady = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    MetricResourceTimeToReady,
+			Help:    "Duration between the creation of a resource and the first time it is observed ready",
+			Buckets: timeToReadyBuckets,
+		},
+		[]string{"resource_group", "resource_id"},
+	)
+
+	resourceOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricResourceOperationsTotal,
+			Help: "Total number of operations made on the resources of the instances by GVK and operation",
+		},
+		[]string{"gvk", "operation"},
+	)
+
+	resourceOperationErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricResourceOperationErrors,
+			Help: "Total number of failed operations made on the resources of the instances by GVK and operation",
+		},
+		[]string{"gvk", "operation"},
+	)
 )
 
 func recordImpersonateError(namespace, sa string, category errorCategory) {
 	impersonationErrors.WithLabelValues(namespace, sa, string(category)).Inc()
 }
 
+func recordImpersonationCacheLookup(namespace, sa string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	impersonationCacheTotal.WithLabelValues(namespace, sa, result).Inc()
+}
+
+func recordResourceOperation(gvk schema.GroupVersionKind, operation string, err error) {
+	key := fmt.Sprintf("%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind)
+	resourceOperationsTotal.WithLabelValues(key, operation).Inc()
+	if err != nil {
+		resourceOperationErrors.WithLabelValues(key, operation).Inc()
+	}
+}
+
+// instances tracks the state of the instances of all the resource groups.
+// Instance controllers are recreated every time their resource group is
+// reconciled, the tracker outlives them so that the gauges stay accurate.
+var instances = newInstanceTracker()
+
+// instanceTracker keeps track of the last known state of the instances, and
+// of the resources waiting to become ready, to maintain the instance metrics.
+type instanceTracker struct {
+	mu sync.Mutex
+	// instances maps resource group names to the instances of the resource
+	// group, indexed by namespace/name.
+	instances map[string]map[string]*trackedInstance
+}
+
+type trackedInstance struct {
+	state string
+	// pendingResources are the resources observed not ready. Only those are
+	// reported once ready, so that the resources that were already ready when
+	// the controller started aren't.
+	pendingResources map[string]struct{}
+}
+
+func newInstanceTracker() *instanceTracker {
+	return &instanceTracker{
+		instances: make(map[string]map[string]*trackedInstance),
+	}
+}
+
+func (t *instanceTracker) get(resourceGroup, key string) *trackedInstance {
+	rgInstances, ok := t.instances[resourceGroup]
+	if !ok {
+		rgInstances = make(map[string]*trackedInstance)
+		t.instances[resourceGroup] = rgInstances
+	}
+	instance, ok := rgInstances[key]
+	if !ok {
+		instance = &trackedInstance{pendingResources: make(map[string]struct{})}
+		rgInstances[key] = instance
+	}
+	return instance
+}
+
+// setState records the state of an instance. The time to ready is observed
+// when an instance becomes active for the first time, i.e coming from no
+// state, from IN_PROGRESS or from DEGRADED.
+func (t *instanceTracker) setState(resourceGroup, key, previousState, state string, created time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	instance := t.get(resourceGroup, key)
+	if instance.state != state {
+		if instance.state != "" {
+			instanceCount.WithLabelValues(resourceGroup, instance.state).Dec()
+		}
+		instanceCount.WithLabelValues(resourceGroup, state).Inc()
+		instance.state = state
+	}
+
+	if state == InstanceStateActive && (previousState == "" || previousState == InstanceStateInProgress || previousState == InstanceStateDegraded) {
+		instanceTimeToReady.WithLabelValues(resourceGroup).Observe(time.Since(created).Seconds())
+	}
+}
+
+// resourcePending records that a resource of an instance isn't ready yet.
+func (t *instanceTracker) resourcePending(resourceGroup, key, resourceID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.get(resourceGroup, key).pendingResources[resourceID] = struct{}{}
+}
+
+// resourceReady records that a resource of an instance is ready, observing
+// its time to ready if it was pending.
+func (t *instanceTracker) resourceReady(resourceGroup, key, resourceID string, created time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	instance := t.get(resourceGroup, key)
+	if _, ok := instance.pendingResources[resourceID]; !ok {
+		return
+	}
+	delete(instance.pendingResources, resourceID)
+	resourceTimeToReady.WithLabelValues(resourceGroup, resourceID).Observe(time.Since(created).Seconds())
+}
+
+// forget stops tracking an instance that doesn't exist anymore.
+func (t *instanceTracker) forget(resourceGroup, key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	instance, ok := t.instances[resourceGroup][key]
+	if !ok {
+		return
+	}
+	if instance.state != "" {
+		instanceCount.WithLabelValues(resourceGroup, instance.state).Dec()
+	}
+	delete(t.instances[resourceGroup], key)
+}
+
+// forgetResourceGroup stops tracking all the instances of a resource group,
+// and deletes its metrics.
+func (t *instanceTracker) forgetResourceGroup(resourceGroup string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.instances, resourceGroup)
+	labels := prometheus.Labels{"resource_group": resourceGroup}
+	instanceCount.DeletePartialMatch(labels)
+	instanceTimeToReady.DeletePartialMatch(labels)
+	resourceTimeToReady.DeletePartialMatch(labels)
+}
+
+// DeleteResourceGroupMetrics deletes the instance metrics of a resource group.
+// It is meant to be called once the resource group is deleted.
+func DeleteResourceGroupMetrics(resourceGroup string) {
+	instances.forgetResourceGroup(resourceGroup)
+}
+
 func init() {
 	metrics.Registry.MustRegister(
 		impersonationTotal,
 		impersonationErrors,
 		impersonationDuration,
+		impersonationCacheTotal,
+		instanceCount,
+		instanceTimeToReady,
+		resourceTimeToReady,
+		resourceOperationsTotal,
+		resourceOperationErrors,
 	)
 }
@@ -0,0 +1,201 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleCount returns the number of observations of a histogram.
+func sampleCount(t *testing.T, histogram *prometheus.HistogramVec, labels ...string) uint64 {
+	var m dto.Metric
+	require.NoError(t, histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestInstanceTrackerSetState(t *testing.T) {
+	type transition struct {
+		previousState string
+		state         string
+	}
+
+	tests := []struct {
+		name          string
+		transitions   []transition
+		wantCounts    map[string]float64
+		wantTimeReady uint64
+	}{
+		{
+			name: "new instance",
+			transitions: []transition{
+				{state: InstanceStateInProgress},
+			},
+			wantCounts: map[string]float64{InstanceStateInProgress: 1},
+		},
+		{
+			name: "instance becoming active",
+			transitions: []transition{
+				{state: InstanceStateInProgress},
+				{previousState: InstanceStateInProgress, state: InstanceStateActive},
+			},
+			wantCounts:    map[string]float64{InstanceStateInProgress: 0, InstanceStateActive: 1},
+			wantTimeReady: 1,
+		},
+		{
+			name: "active instance reconciled again",
+			transitions: []transition{
+				{state: InstanceStateActive},
+				{previousState: InstanceStateActive, state: InstanceStateActive},
+			},
+			wantCounts:    map[string]float64{InstanceStateActive: 1},
+			wantTimeReady: 1,
+		},
+		{
+			name: "degraded instance recovering",
+			transitions: []transition{
+				{previousState: InstanceStateInProgress, state: InstanceStateDegraded},
+				{previousState: InstanceStateDegraded, state: InstanceStateActive},
+			},
+			wantCounts:    map[string]float64{InstanceStateDegraded: 0, InstanceStateActive: 1},
+			wantTimeReady: 1,
+		},
+		{
+			name: "instance recovering from an error",
+			transitions: []transition{
+				{previousState: InstanceStateActive, state: InstanceStateError},
+				{previousState: InstanceStateError, state: InstanceStateActive},
+			},
+			wantCounts: map[string]float64{InstanceStateError: 0, InstanceStateActive: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceGroup := "set-state-" + tt.name
+			tracker := newInstanceTracker()
+			defer tracker.forgetResourceGroup(resourceGroup)
+
+			for _, transition := range tt.transitions {
+				tracker.setState(resourceGroup, "default/my-app", transition.previousState, transition.state, time.Now())
+			}
+
+			for state, count := range tt.wantCounts {
+				assert.Equal(t, count, testutil.ToFloat64(instanceCount.WithLabelValues(resourceGroup, state)), state)
+			}
+			assert.Equal(t, tt.wantTimeReady, sampleCount(t, instanceTimeToReady, resourceGroup))
+		})
+	}
+}
+
+func TestInstanceTrackerForget(t *testing.T) {
+	tests := []struct {
+		name       string
+		forget     []string
+		wantActive float64
+	}{
+		{
+			name:       "tracked instance",
+			forget:     []string{"default/first"},
+			wantActive: 1,
+		},
+		{
+			name:       "instance forgotten twice",
+			forget:     []string{"default/first", "default/first"},
+			wantActive: 1,
+		},
+		{
+			name:       "unknown instance",
+			forget:     []string{"default/unknown"},
+			wantActive: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceGroup := "forget-" + tt.name
+			tracker := newInstanceTracker()
+			defer tracker.forgetResourceGroup(resourceGroup)
+
+			tracker.setState(resourceGroup, "default/first", "", InstanceStateActive, time.Now())
+			tracker.setState(resourceGroup, "default/second", "", InstanceStateActive, time.Now())
+			for _, key := range tt.forget {
+				tracker.forget(resourceGroup, key)
+			}
+
+			assert.Equal(t, tt.wantActive, testutil.ToFloat64(instanceCount.WithLabelValues(resourceGroup, InstanceStateActive)))
+			for _, key := range tt.forget {
+				assert.NotContains(t, tracker.instances[resourceGroup], key)
+			}
+		})
+	}
+}
+
+func TestInstanceTrackerResources(t *testing.T) {
+	tests := []struct {
+		name          string
+		pending       bool
+		ready         int
+		wantTimeReady uint64
+		wantPending   bool
+	}{
+		{
+			name:        "pending resource",
+			pending:     true,
+			wantPending: true,
+		},
+		{
+			name:          "pending resource becoming ready",
+			pending:       true,
+			ready:         1,
+			wantTimeReady: 1,
+		},
+		{
+			name:          "ready resource observed again",
+			pending:       true,
+			ready:         2,
+			wantTimeReady: 1,
+		},
+		{
+			name:  "resource ready when first observed",
+			ready: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceGroup := "resources-" + tt.name
+			tracker := newInstanceTracker()
+			defer tracker.forgetResourceGroup(resourceGroup)
+
+			if tt.pending {
+				tracker.resourcePending(resourceGroup, "default/my-app", "database")
+				tracker.resourcePending(resourceGroup, "default/my-app", "database")
+			}
+			for i := 0; i < tt.ready; i++ {
+				tracker.resourceReady(resourceGroup, "default/my-app", "database", time.Now())
+			}
+
+			assert.Equal(t, tt.wantTimeReady, sampleCount(t, resourceTimeToReady, resourceGroup, "database"))
+			_, pending := tracker.get(resourceGroup, "default/my-app").pendingResources["database"]
+			assert.Equal(t, tt.wantPending, pending)
+		})
+	}
+}
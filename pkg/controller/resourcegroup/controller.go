@@ -15,12 +15,18 @@ package resourcegroup
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/awslabs/kro/api/v1alpha1"
@@ -33,6 +39,16 @@ import (
 //+kubebuilder:rbac:groups=kro.run,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kro.run,resources=resourcegroups/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kro.run,resources=resourcegroups/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// deletionBlockedRequeueAfter is the interval at which the remaining instances
+// of a resource group being deleted are counted.
+const deletionBlockedRequeueAfter = 30 * time.Second
+
+// kindConflictRequeueAfter is the interval at which a resource group whose
+// kind is claimed by another resource group tries to claim it again, e.g once
+// the other resource group is deleted.
+const kindConflictRequeueAfter = 30 * time.Second
 
 // ResourceGroupReconciler reconciles a ResourceGroup object
 type ResourceGroupReconciler struct {
@@ -48,6 +64,12 @@ type ResourceGroupReconciler struct {
 	metadataLabeler   metadata.Labeler
 	rgBuilder         *graph.Builder
 	dynamicController *dynamiccontroller.DynamicController
+	// recorder records events on the resource groups and their instances.
+	recorder record.EventRecorder
+	// paused tracks the resource groups whose instances are paused, keyed by
+	// namespace/name, so that the instances are requeued when they're paused
+	// or resumed.
+	paused sync.Map
 }
 
 func NewResourceGroupReconciler(
@@ -57,6 +79,7 @@ func NewResourceGroupReconciler(
 	allowCRDDeletion bool,
 	dynamicController *dynamiccontroller.DynamicController,
 	builder *graph.Builder,
+	recorder record.EventRecorder,
 ) *ResourceGroupReconciler {
 	crdWrapper := clientSet.CRD(kroclient.CRDWrapperConfig{
 		Log: log,
@@ -73,6 +96,7 @@ func NewResourceGroupReconciler(
 		dynamicController: dynamicController,
 		metadataLabeler:   metadata.NewKroMetaLabeler("0.1.0", "kro-pod"),
 		rgBuilder:         builder,
+		recorder:          recorder,
 	}
 }
 
@@ -80,6 +104,7 @@ func NewResourceGroupReconciler(
 func (r *ResourceGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.ResourceGroup{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Complete(reconcile.AsReconciler[*v1alpha1.ResourceGroup](mgr.GetClient(), r))
 }
 
@@ -89,14 +114,41 @@ func (r *ResourceGroupReconciler) Reconcile(ctx context.Context, resourcegroup *
 
 	if !resourcegroup.DeletionTimestamp.IsZero() {
 		rlog.V(1).Info("ResourceGroup is being deleted")
-		if err := r.cleanupResourceGroup(ctx, resourcegroup); err != nil {
+
+		// A resource group in conflict doesn't own the kind it defines, the
+		// CRD, instances and micro controller of the owner are left untouched.
+		owned, err := r.ownsKind(ctx, resourcegroup)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+		if owned {
+			// Deleting the resource group orphans its instances, or deletes them
+			// along with the CRD. Hold the deletion until they're gone.
+			remaining, err := r.countRemainingInstances(ctx, resourcegroup)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if remaining > 0 && !metadata.ForcesDeletion(resourcegroup.GetAnnotations()) {
+				rlog.Info("ResourceGroup deletion blocked by remaining instances", "instances", remaining)
+				r.recorder.Eventf(resourcegroup, corev1.EventTypeWarning, EventReasonDeletionBlocked,
+					"%d instances of %s still exist", remaining, resourcegroup.Spec.Schema.Kind)
+				if err := r.setDeletionBlockedStatus(ctx, resourcegroup, remaining); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: deletionBlockedRequeueAfter}, nil
+			}
+
+			if err := r.cleanupResourceGroup(ctx, resourcegroup); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 
 		rlog.V(1).Info("Setting resourcegroup as unmanaged")
 		if err := r.setUnmanaged(ctx, resourcegroup); err != nil {
 			return ctrl.Result{}, err
 		}
+		resourceGroupStates.forget(client.ObjectKeyFromObject(resourcegroup).String())
+		r.paused.Delete(client.ObjectKeyFromObject(resourcegroup).String())
 
 		return ctrl.Result{}, nil
 	}
@@ -107,12 +159,37 @@ func (r *ResourceGroupReconciler) Reconcile(ctx context.Context, resourcegroup *
 	}
 
 	rlog.V(1).Info("Syncing resourcegroup")
-	topologicalOrder, resourcesInformation, reconcileErr := r.reconcileResourceGroup(ctx, resourcegroup)
+	processedRG, resourcesInformation, crdChanges, reconcileErr := r.reconcileResourceGroup(ctx, resourcegroup)
+	if reconcileErr != nil {
+		r.recordReconcileError(resourcegroup, reconcileErr)
+		recordReconcileErrorMetric(reconcileErr)
+	}
+
+	var serviceAccountsErr error
+	if reconcileErr == nil {
+		rlog.V(1).Info("Verifying default service accounts permissions")
+		serviceAccountsErr = r.verifyServiceAccounts(ctx, resourcegroup, processedRG)
+		if serviceAccountsErr != nil {
+			r.recorder.Event(resourcegroup, corev1.EventTypeWarning, EventReasonMissingPermissions, serviceAccountsErr.Error())
+		}
+	}
 
 	rlog.V(1).Info("Setting resourcegroup status")
-	if err := r.setResourceGroupStatus(ctx, resourcegroup, topologicalOrder, resourcesInformation, reconcileErr); err != nil {
+	if err := r.setResourceGroupStatus(
+		ctx,
+		resourcegroup,
+		processedRG,
+		resourcesInformation,
+		crdChanges,
+		reconcileErr,
+		serviceAccountsErr,
+	); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	var conflictErr *kroclient.OwnerConflictError
+	if errors.As(reconcileErr, &conflictErr) {
+		return ctrl.Result{RequeueAfter: kindConflictRequeueAfter}, nil
+	}
 	return ctrl.Result{}, nil
 }
@@ -20,15 +20,20 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/gobuffalo/flect"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/awslabs/kro/api/v1alpha1"
+	instancectrl "github.com/awslabs/kro/pkg/controller/instance"
 	"github.com/awslabs/kro/pkg/metadata"
 )
 
 // cleanupResourceGroup handles the deletion of a ResourceGroup by shutting down its associated
 // microcontroller and cleaning up the CRD if enabled. It executes cleanup operations in order:
-// 1. Shuts down the microcontroller
+// 1. Shuts down the microcontroller and deletes the metrics of its instances
 // 2. Deletes the associated CRD (if CRD deletion is enabled)
 func (r *ResourceGroupReconciler) cleanupResourceGroup(ctx context.Context, rg *v1alpha1.ResourceGroup) error {
 	log, _ := logr.FromContext(ctx)
@@ -39,16 +44,47 @@ func (r *ResourceGroupReconciler) cleanupResourceGroup(ctx context.Context, rg *
 	if err := r.shutdownResourceGroupMicroController(ctx, &gvr); err != nil {
 		return fmt.Errorf("failed to shutdown microcontroller: %w", err)
 	}
+	instancectrl.DeleteResourceGroupMetrics(rg.Name)
 
 	// cleanup CRD
 	crdName := extractCRDName(rg.Spec.Schema.Kind)
-	if err := r.cleanupResourceGroupCRD(ctx, crdName); err != nil {
+	if err := r.cleanupResourceGroupCRD(ctx, crdName, client.ObjectKeyFromObject(rg).String()); err != nil {
 		return fmt.Errorf("failed to cleanup CRD %s: %w", crdName, err)
 	}
 
 	return nil
 }
 
+// ownsKind returns true if the resource group claims the kind it defines. CRDs
+// without an owner, e.g created by earlier versions of kro, are owned by any
+// resource group defining their kind.
+func (r *ResourceGroupReconciler) ownsKind(ctx context.Context, rg *v1alpha1.ResourceGroup) (bool, error) {
+	crd, err := r.crdManager.Get(ctx, extractCRDName(rg.Spec.Schema.Kind))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get CRD: %w", err)
+	}
+	owner := crd.Annotations[metadata.ResourceGroupOwnerAnnotation]
+	return owner == "" || owner == client.ObjectKeyFromObject(rg).String(), nil
+}
+
+// countRemainingInstances returns the number of instances of the resource group
+// that still exist, across all namespaces.
+func (r *ResourceGroupReconciler) countRemainingInstances(ctx context.Context, rg *v1alpha1.ResourceGroup) (int, error) {
+	gvr := metadata.GetResourceGroupInstanceGVR(rg.Spec.Schema.APIVersion, rg.Spec.Schema.Kind)
+	instances, err := r.clientSet.Dynamic().Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// The CRD is gone, and so are the instances.
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list instances: %w", err)
+	}
+	return len(instances.Items), nil
+}
+
 // shutdownResourceGroupMicroController stops the dynamic controller associated with the given GVR.
 // This ensures no new reconciliations occur for this resource type.
 func (r *ResourceGroupReconciler) shutdownResourceGroupMicroController(ctx context.Context, gvr *schema.GroupVersionResource) error {
@@ -59,11 +95,15 @@ func (r *ResourceGroupReconciler) shutdownResourceGroupMicroController(ctx conte
 }
 
 // cleanupResourceGroupCRD deletes the CRD with the given name if CRD deletion is enabled.
-// If CRD deletion is disabled, it logs the skip and returns nil.
-func (r *ResourceGroupReconciler) cleanupResourceGroupCRD(ctx context.Context, crdName string) error {
+// If CRD deletion is disabled, the claim of the resource group on the CRD is released,
+// so that another resource group defining the same kind can claim it.
+func (r *ResourceGroupReconciler) cleanupResourceGroupCRD(ctx context.Context, crdName, owner string) error {
 	if !r.allowCRDDeletion {
 		log, _ := logr.FromContext(ctx)
 		log.Info("skipping CRD deletion (disabled)", "crd", crdName)
+		if err := r.crdManager.Release(ctx, crdName, owner); err != nil {
+			return fmt.Errorf("error releasing CRD: %w", err)
+		}
 		return nil
 	}
 
@@ -0,0 +1,55 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupResourceGroupCRD(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowCRDDeletion bool
+		wantDeleted      []string
+		wantReleased     map[string]string
+	}{
+		{
+			name:             "CRD deletion allowed",
+			allowCRDDeletion: true,
+			wantDeleted:      []string{"webapps.kro.run"},
+		},
+		{
+			name:         "CRD deletion disabled",
+			wantReleased: map[string]string{"webapps.kro.run": "default/webapp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crdClient := &fakeCRDClient{}
+			r := &ResourceGroupReconciler{
+				allowCRDDeletion: tt.allowCRDDeletion,
+				crdManager:       crdClient,
+			}
+
+			require.NoError(t, r.cleanupResourceGroupCRD(context.Background(), "webapps.kro.run", "default/webapp"))
+			assert.Equal(t, tt.wantDeleted, crdClient.deleted)
+			assert.Equal(t, tt.wantReleased, crdClient.released)
+		})
+	}
+}
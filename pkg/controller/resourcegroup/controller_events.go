@@ -0,0 +1,59 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resourcegroup
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+)
+
+// Reasons of the events recorded on resource groups.
+const (
+	EventReasonCRDSynced            = "CustomResourceDefinitionSynced"
+	EventReasonInvalidGraph         = "InvalidGraph"
+	EventReasonCRDSyncFailed        = "CustomResourceDefinitionSyncFailed"
+	EventReasonKindConflict         = "KindConflict"
+	EventReasonReconcilerFailed     = "ReconcilerFailed"
+	EventReasonMissingPermissions   = "MissingPermissions"
+	EventReasonReconciliationFailed = "ReconciliationFailed"
+	EventReasonDeletionBlocked      = "DeletionBlocked"
+	EventReasonInstancesPaused      = "InstancesPaused"
+	EventReasonInstancesResumed     = "InstancesResumed"
+)
+
+// recordReconcileError records a warning event describing why the resource
+// group couldn't be reconciled.
+func (r *ResourceGroupReconciler) recordReconcileError(rg *v1alpha1.ResourceGroup, err error) {
+	reason := EventReasonReconciliationFailed
+	switch errorClass(err) {
+	case errorClassGraph:
+		reason = EventReasonInvalidGraph
+	case errorClassKindConflict:
+		reason = EventReasonKindConflict
+	case errorClassCRD:
+		reason = EventReasonCRDSyncFailed
+	case errorClassMicroController:
+		reason = EventReasonReconcilerFailed
+	}
+	r.recorder.Event(rg, corev1.EventTypeWarning, reason, err.Error())
+}
+
+// isCRDSynced reports whether the status of the resource group already reports
+// its CRD as synced.
+func isCRDSynced(rg *v1alpha1.ResourceGroup) bool {
+	condition := v1alpha1.GetCondition(rg.Status.Conditions, v1alpha1.ResourceGroupConditionTypeCustomResourceDefinitionSynced)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
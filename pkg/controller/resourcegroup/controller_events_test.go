@@ -0,0 +1,156 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+// fakeCRDClient ensures CRDs by returning fixed schema changes, and records
+// the deleted and released CRDs.
+type fakeCRDClient struct {
+	kroclient.CRDClient
+	changes  kroclient.SchemaChanges
+	err      error
+	deleted  []string
+	released map[string]string
+}
+
+func (f *fakeCRDClient) Ensure(_ context.Context, _ v1.CustomResourceDefinition, _ ...kroclient.EnsureOption) (kroclient.SchemaChanges, error) {
+	return f.changes, f.err
+}
+
+func (f *fakeCRDClient) Delete(_ context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	return f.err
+}
+
+func (f *fakeCRDClient) Release(_ context.Context, name, owner string) error {
+	if f.released == nil {
+		f.released = make(map[string]string)
+	}
+	f.released[name] = owner
+	return f.err
+}
+
+// drainEvents returns the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestReconcileResourceGroupCRDEvents(t *testing.T) {
+	crdSynced := func(status metav1.ConditionStatus) []v1alpha1.Condition {
+		return []v1alpha1.Condition{
+			newCustomResourceDefinitionSyncedCondition(status, ""),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []v1alpha1.Condition
+		changes    kroclient.SchemaChanges
+		ensureErr  error
+		wantEvents []string
+	}{
+		{
+			name: "created CRD",
+			wantEvents: []string{
+				"Normal CustomResourceDefinitionSynced Synced CustomResourceDefinition webapps.kro.run",
+			},
+		},
+		{
+			name:       "CRD synced after failing to sync",
+			conditions: crdSynced(metav1.ConditionFalse),
+			wantEvents: []string{
+				"Normal CustomResourceDefinitionSynced Synced CustomResourceDefinition webapps.kro.run",
+			},
+		},
+		{
+			name:       "CRD already synced",
+			conditions: crdSynced(metav1.ConditionTrue),
+		},
+		{
+			name:       "CRD schema changed",
+			conditions: crdSynced(metav1.ConditionTrue),
+			changes: kroclient.SchemaChanges{
+				{Version: "v1alpha1", Path: "spec.replicas", Type: kroclient.SchemaChangeFieldAdded},
+			},
+			wantEvents: []string{
+				"Normal CustomResourceDefinitionSynced Synced CustomResourceDefinition webapps.kro.run: v1alpha1 spec.replicas: FieldAdded",
+			},
+		},
+		{
+			name:       "CRD failed to sync",
+			conditions: crdSynced(metav1.ConditionTrue),
+			ensureErr:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &ResourceGroupReconciler{
+				crdManager: &fakeCRDClient{changes: tt.changes, err: tt.ensureErr},
+				recorder:   recorder,
+			}
+			rg := &v1alpha1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "webapp", Namespace: "default"},
+				Status:     v1alpha1.ResourceGroupStatus{Conditions: tt.conditions},
+			}
+			crd := &v1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "webapps.kro.run"}}
+
+			_, err := r.reconcileResourceGroupCRD(context.Background(), rg, crd, metadata.NewResourceGroupLabeler(rg))
+			if tt.ensureErr != nil {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantEvents, drainEvents(recorder))
+		})
+	}
+}
+
+func TestRecordReconcileError(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &ResourceGroupReconciler{recorder: recorder}
+	rg := &v1alpha1.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "webapp", Namespace: "default"}}
+
+	r.recordReconcileError(rg, newCRDError(errors.New("connection refused")))
+	r.recordReconcileError(rg, &kroclient.OwnerConflictError{Name: "webapps.kro.run", Owner: "default/other"})
+
+	events := drainEvents(recorder)
+	require.Len(t, events, 2)
+	assert.Contains(t, events[0], "Warning CustomResourceDefinitionSyncFailed")
+	assert.Equal(t, "Warning KindConflict CRD webapps.kro.run is already claimed by resource group default/other", events[1])
+}
@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/graph"
+)
+
+// verifyServiceAccounts checks, with SelfSubjectAccessReviews, that the default
+// service accounts of the resource group are allowed to manage the resources of
+// the graph in their namespace. The default service account of the "*" key is
+// skipped, the namespaces it applies to are only known once instances exist.
+func (r *ResourceGroupReconciler) verifyServiceAccounts(
+	ctx context.Context,
+	rg *v1alpha1.ResourceGroup,
+	processedRG *graph.Graph,
+) error {
+	namespaces := make([]string, 0, len(rg.Spec.DefaultServiceAccounts))
+	for namespace := range rg.Spec.DefaultServiceAccounts {
+		if namespace != v1alpha1.DefaultServiceAccountKey {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	var problems []string
+	for _, namespace := range namespaces {
+		sa := rg.Spec.DefaultServiceAccounts[namespace]
+		userName := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, sa)
+		set, _, err := r.clientSet.WithCachedImpersonation(userName)
+		if err != nil {
+			return fmt.Errorf("failed to create impersonated client for %s: %w", userName, err)
+		}
+
+		missing, err := set.MissingPermissions(ctx, graphPermissions(processedRG, namespace))
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		permissions := make([]string, 0, len(missing))
+		for _, permission := range missing {
+			permissions = append(permissions, permission.String())
+		}
+		problems = append(problems, fmt.Sprintf("service account %s/%s is missing permissions: %s",
+			namespace, sa, strings.Join(permissions, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// graphPermissions returns the permissions needed to manage the resources of
+// the graph for instances of the given namespace.
+func graphPermissions(processedRG *graph.Graph, namespace string) []kroclient.Permission {
+	var permissions []kroclient.Permission
+	for _, resourceID := range processedRG.TopologicalOrder {
+		resource := processedRG.Resources[resourceID]
+		gvr := resource.GetGroupVersionResource()
+
+		var resourceNamespace string
+		if resource.IsNamespaced() {
+			resourceNamespace = namespace
+			// Resources can live in another namespace than their instance.
+			// Expressions are only resolved at runtime, use the namespace of
+			// the instance for those.
+			if ns := resource.Unstructured().GetNamespace(); ns != "" && !strings.Contains(ns, "${") {
+				resourceNamespace = ns
+			}
+		}
+
+		for _, verb := range []string{"get", "create", "delete"} {
+			permissions = append(permissions, kroclient.Permission{
+				Verb:      verb,
+				Group:     gvr.Group,
+				Resource:  gvr.Resource,
+				Namespace: resourceNamespace,
+			})
+		}
+	}
+	return permissions
+}
@@ -19,10 +19,13 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
 	instancectrl "github.com/awslabs/kro/pkg/controller/instance"
 	"github.com/awslabs/kro/pkg/dynamiccontroller"
 	"github.com/awslabs/kro/pkg/graph"
@@ -33,38 +36,62 @@ import (
 // 1. Processing the resource graph
 // 2. Ensuring CRDs are present
 // 3. Setting up and starting the microcontroller
-func (r *ResourceGroupReconciler) reconcileResourceGroup(ctx context.Context, rg *v1alpha1.ResourceGroup) ([]string, []v1alpha1.ResourceInformation, error) {
+//
+// The changes made to the schema of the CRD are returned along with the graph.
+func (r *ResourceGroupReconciler) reconcileResourceGroup(
+	ctx context.Context,
+	rg *v1alpha1.ResourceGroup,
+) (*graph.Graph, []v1alpha1.ResourceInformation, kroclient.SchemaChanges, error) {
 	log, _ := logr.FromContext(ctx)
 
 	// Process resource group graph first to validate structure
 	log.V(1).Info("reconciling resource group graph")
 	processedRG, resourcesInfo, err := r.reconcileResourceGroupGraph(ctx, rg)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	// Ensure CRD exists and is up to date
-	log.V(1).Info("reconciling resource group CRD")
-	if err := r.reconcileResourceGroupCRD(ctx, processedRG.Instance.GetCRD()); err != nil {
-		return processedRG.TopologicalOrder, resourcesInfo, err
+		return nil, nil, nil, err
 	}
 
 	// Setup metadata labeling
 	graphExecLabeler, err := r.setupLabeler(rg)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to setup labeler: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to setup labeler: %w", err)
+	}
+
+	// Ensure CRD exists and is up to date
+	log.V(1).Info("reconciling resource group CRD")
+	crdChanges, err := r.reconcileResourceGroupCRD(ctx, rg, processedRG.Instance.GetCRD(), graphExecLabeler)
+	if err != nil {
+		return processedRG, resourcesInfo, crdChanges, err
 	}
 
 	// Setup and start microcontroller
 	gvr := processedRG.Instance.GetGroupVersionResource()
-	controller := r.setupMicroController(gvr, processedRG, rg.Spec.DefaultServiceAccounts, graphExecLabeler)
+	paused := metadata.IsPaused(rg.GetAnnotations())
+	reconcileInterval := instancectrl.DefaultReconcileInterval
+	if rg.Spec.ReconcileInterval != nil {
+		reconcileInterval = rg.Spec.ReconcileInterval.Duration
+	}
+	controller := r.setupMicroController(
+		gvr,
+		processedRG,
+		rg.Spec.DefaultServiceAccounts,
+		rg.Spec.AllowedServiceAccounts,
+		graphExecLabeler,
+		paused,
+		reconcileInterval,
+	)
 
 	log.V(1).Info("reconciling resource group micro controller")
-	if err := r.reconcileResourceGroupMicroController(ctx, &gvr, controller.Reconcile); err != nil {
-		return processedRG.TopologicalOrder, resourcesInfo, err
+	var gvrOpts []dynamiccontroller.GVROption
+	if rg.Spec.QueueWeight != nil {
+		gvrOpts = append(gvrOpts, dynamiccontroller.WithQueueWeight(int(*rg.Spec.QueueWeight)))
 	}
+	if err := r.reconcileResourceGroupMicroController(ctx, &gvr, controller.Reconcile, gvrOpts...); err != nil {
+		return processedRG, resourcesInfo, crdChanges, err
+	}
+	r.reconcileResourceGroupPause(rg, gvr, paused)
 
-	return processedRG.TopologicalOrder, resourcesInfo, nil
+	return processedRG, resourcesInfo, crdChanges, nil
 }
 
 // setupLabeler creates and merges the required labelers for the resource group
@@ -78,7 +105,10 @@ func (r *ResourceGroupReconciler) setupMicroController(
 	gvr schema.GroupVersionResource,
 	processedRG *graph.Graph,
 	defaultSVCs map[string]string,
+	allowedSVCs []string,
 	labeler metadata.Labeler,
+	paused bool,
+	reconcileInterval time.Duration,
 ) *instancectrl.Controller {
 
 	instanceLogger := r.rootLogger.WithName("controller." + gvr.Resource)
@@ -89,19 +119,23 @@ func (r *ResourceGroupReconciler) setupMicroController(
 			DefaultRequeueDuration:    3 * time.Second,
 			DeletionGraceTimeDuration: 30 * time.Second,
 			DeletionPolicy:            "Delete",
+			Paused:                    paused,
+			ReconcileInterval:         reconcileInterval,
 		},
 		gvr,
 		processedRG,
 		r.clientSet,
 		defaultSVCs,
+		allowedSVCs,
 		labeler,
+		r.recorder,
 	)
 }
 
 // reconcileResourceGroupGraph processes the resource group to build a dependency graph
 // and extract resource information
-func (r *ResourceGroupReconciler) reconcileResourceGroupGraph(_ context.Context, rg *v1alpha1.ResourceGroup) (*graph.Graph, []v1alpha1.ResourceInformation, error) {
-	processedRG, err := r.rgBuilder.NewResourceGroup(rg)
+func (r *ResourceGroupReconciler) reconcileResourceGroupGraph(ctx context.Context, rg *v1alpha1.ResourceGroup) (*graph.Graph, []v1alpha1.ResourceInformation, error) {
+	processedRG, err := r.rgBuilder.NewResourceGroup(ctx, rg)
 	if err != nil {
 		return nil, nil, newGraphError(err)
 	}
@@ -129,23 +163,72 @@ func buildResourceInfo(name string, deps []string) v1alpha1.ResourceInformation
 	}
 }
 
-// reconcileResourceGroupCRD ensures the CRD is present and up to date in the cluster
-func (r *ResourceGroupReconciler) reconcileResourceGroupCRD(ctx context.Context, crd *v1.CustomResourceDefinition) error {
-	if err := r.crdManager.Ensure(ctx, *crd); err != nil {
-		return newCRDError(err)
+// reconcileResourceGroupCRD ensures the CRD is present and up to date in the cluster.
+// Breaking changes to the schema of the CRD are refused, unless the resource group
+// opts in through the allow breaking changes annotation. The CRD is claimed by the
+// resource group, a CRD claimed by another resource group is left untouched.
+//
+// The CRD carries the kro labels and the resource group annotation, so that it can
+// be traced back to its definition. It has no owner reference, a cluster-scoped CRD
+// can't be owned by a namespaced resource group.
+func (r *ResourceGroupReconciler) reconcileResourceGroupCRD(
+	ctx context.Context,
+	rg *v1alpha1.ResourceGroup,
+	crd *v1.CustomResourceDefinition,
+	labeler metadata.Labeler,
+) (kroclient.SchemaChanges, error) {
+	crd = crd.DeepCopy()
+	labeler.ApplyLabels(crd)
+
+	changes, err := r.crdManager.Ensure(ctx, *crd,
+		kroclient.AllowBreakingChanges(metadata.AllowsBreakingChanges(rg.GetAnnotations())),
+		kroclient.WithOwner(client.ObjectKeyFromObject(rg).String()),
+	)
+	if err != nil {
+		return changes, newCRDError(err)
 	}
-	return nil
+	// The CRD is synced at every reconciliation, only record when its schema
+	// changes, or when it gets synced after being created or failing to sync.
+	if len(changes) > 0 {
+		r.recorder.Eventf(rg, corev1.EventTypeNormal, EventReasonCRDSynced, "Synced CustomResourceDefinition %s: %s", crd.Name, changes)
+	} else if !isCRDSynced(rg) {
+		r.recorder.Eventf(rg, corev1.EventTypeNormal, EventReasonCRDSynced, "Synced CustomResourceDefinition %s", crd.Name)
+	}
+	return changes, nil
 }
 
 // reconcileResourceGroupMicroController starts the microcontroller for handling the resources
-func (r *ResourceGroupReconciler) reconcileResourceGroupMicroController(ctx context.Context, gvr *schema.GroupVersionResource, handler dynamiccontroller.Handler) error {
-	err := r.dynamicController.StartServingGVK(ctx, *gvr, handler)
+func (r *ResourceGroupReconciler) reconcileResourceGroupMicroController(
+	ctx context.Context,
+	gvr *schema.GroupVersionResource,
+	handler dynamiccontroller.Handler,
+	opts ...dynamiccontroller.GVROption,
+) error {
+	err := r.dynamicController.StartServingGVK(ctx, *gvr, handler, opts...)
 	if err != nil {
 		return newMicroControllerError(err)
 	}
 	return nil
 }
 
+// reconcileResourceGroupPause requeues the instances of the resource group when
+// they're paused or resumed through the paused annotation of the resource group.
+// The instances that didn't change wouldn't pick up the new micro controller
+// otherwise.
+func (r *ResourceGroupReconciler) reconcileResourceGroupPause(rg *v1alpha1.ResourceGroup, gvr schema.GroupVersionResource, paused bool) {
+	previous, _ := r.paused.Swap(client.ObjectKeyFromObject(rg).String(), paused)
+	if wasPaused, _ := previous.(bool); wasPaused == paused {
+		return
+	}
+
+	if paused {
+		r.recorder.Eventf(rg, corev1.EventTypeNormal, EventReasonInstancesPaused, "Paused the reconciliation of the %s instances", rg.Spec.Schema.Kind)
+	} else {
+		r.recorder.Eventf(rg, corev1.EventTypeNormal, EventReasonInstancesResumed, "Resumed the reconciliation of the %s instances", rg.Spec.Schema.Kind)
+	}
+	r.dynamicController.Requeue(gvr)
+}
+
 // Error types for the resourcegroup controller
 type (
 	graphError           struct{ err error }
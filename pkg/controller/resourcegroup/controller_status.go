@@ -23,14 +23,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/graph"
 	"github.com/awslabs/kro/pkg/metadata"
 	"github.com/go-logr/logr"
 )
 
 // StatusProcessor handles the processing of ResourceGroup status updates
 type StatusProcessor struct {
-	conditions []v1alpha1.Condition
-	state      v1alpha1.ResourceGroupState
+	conditions       []v1alpha1.Condition
+	state            v1alpha1.ResourceGroupState
+	validationErrors []v1alpha1.ValidationError
 }
 
 // NewStatusProcessor creates a new StatusProcessor with default active state
@@ -41,16 +44,25 @@ func NewStatusProcessor() *StatusProcessor {
 	}
 }
 
-// setDefaultConditions sets the default conditions for an active resource group
-func (sp *StatusProcessor) setDefaultConditions() {
+// setDefaultConditions sets the default conditions for an active resource group.
+// The changes made to the schema of the CRD, if any, are listed in the CRD synced
+// condition.
+func (sp *StatusProcessor) setDefaultConditions(crdChanges kroclient.SchemaChanges) {
+	crdSynced := newCustomResourceDefinitionSyncedCondition(metav1.ConditionTrue, "")
+	if len(crdChanges) > 0 {
+		message := fmt.Sprintf("%s, schema changes: %s", *crdSynced.Message, crdChanges)
+		crdSynced.Message = &message
+	}
 	sp.conditions = []v1alpha1.Condition{
 		newReconcilerReadyCondition(metav1.ConditionTrue, ""),
 		newGraphVerifiedCondition(metav1.ConditionTrue, ""),
-		newCustomResourceDefinitionSyncedCondition(metav1.ConditionTrue, ""),
+		newKindClaimedCondition(metav1.ConditionTrue, ""),
+		crdSynced,
 	}
 }
 
-// processGraphError handles graph-related errors
+// processGraphError handles graph-related errors. When the graph builder
+// reports validation errors, all of them are listed in the status.
 func (sp *StatusProcessor) processGraphError(err error) {
 	sp.conditions = []v1alpha1.Condition{
 		newGraphVerifiedCondition(metav1.ConditionFalse, err.Error()),
@@ -58,6 +70,43 @@ func (sp *StatusProcessor) processGraphError(err error) {
 		newCustomResourceDefinitionSyncedCondition(metav1.ConditionUnknown, "Faulty Graph"),
 	}
 	sp.state = v1alpha1.ResourceGroupStateInactive
+
+	var validationErrs graph.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		sp.validationErrors = make([]v1alpha1.ValidationError, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			sp.validationErrors = append(sp.validationErrors, v1alpha1.ValidationError{
+				ResourceID: validationErr.ResourceID,
+				Path:       validationErr.Path,
+				Expression: validationErr.Expression,
+				Message:    validationErr.Message,
+			})
+		}
+	}
+}
+
+// processServiceAccountsVerification reports whether the default service
+// accounts are allowed to manage the resources of the graph. Missing permissions
+// don't prevent the resource group from being active, instances reconciled with
+// another identity aren't affected.
+func (sp *StatusProcessor) processServiceAccountsVerification(err error) {
+	if err != nil {
+		sp.conditions = append(sp.conditions, newServiceAccountsVerifiedCondition(metav1.ConditionFalse, err.Error()))
+		return
+	}
+	sp.conditions = append(sp.conditions, newServiceAccountsVerifiedCondition(metav1.ConditionTrue, ""))
+}
+
+// processKindConflict handles resource groups defining a kind already claimed
+// by another resource group.
+func (sp *StatusProcessor) processKindConflict(kind string, err *kroclient.OwnerConflictError) {
+	sp.conditions = []v1alpha1.Condition{
+		newGraphVerifiedCondition(metav1.ConditionTrue, ""),
+		newKindClaimedCondition(metav1.ConditionFalse, fmt.Sprintf("kind %s is already claimed by resource group %s", kind, err.Owner)),
+		newCustomResourceDefinitionSyncedCondition(metav1.ConditionUnknown, "Kind conflict"),
+		newReconcilerReadyCondition(metav1.ConditionUnknown, "Kind conflict"),
+	}
+	sp.state = v1alpha1.ResourceGroupStateConflict
 }
 
 // processCRDError handles CRD-related errors
@@ -74,6 +123,7 @@ func (sp *StatusProcessor) processCRDError(err error) {
 func (sp *StatusProcessor) processMicroControllerError(err error) {
 	sp.conditions = []v1alpha1.Condition{
 		newGraphVerifiedCondition(metav1.ConditionTrue, ""),
+		newKindClaimedCondition(metav1.ConditionTrue, ""),
 		newCustomResourceDefinitionSyncedCondition(metav1.ConditionTrue, ""),
 		newReconcilerReadyCondition(metav1.ConditionFalse, err.Error()),
 	}
@@ -85,9 +135,11 @@ func (sp *StatusProcessor) processMicroControllerError(err error) {
 func (r *ResourceGroupReconciler) setResourceGroupStatus(
 	ctx context.Context,
 	resourcegroup *v1alpha1.ResourceGroup,
-	topologicalOrder []string,
+	processedRG *graph.Graph,
 	resources []v1alpha1.ResourceInformation,
+	crdChanges kroclient.SchemaChanges,
 	reconcileErr error,
+	serviceAccountsErr error,
 ) error {
 	log, _ := logr.FromContext(ctx)
 	log.V(1).Info("calculating resource group status and conditions")
@@ -95,17 +147,21 @@ func (r *ResourceGroupReconciler) setResourceGroupStatus(
 	processor := NewStatusProcessor()
 
 	if reconcileErr == nil {
-		processor.setDefaultConditions()
+		processor.setDefaultConditions(crdChanges)
+		processor.processServiceAccountsVerification(serviceAccountsErr)
 	} else {
 		log.V(1).Info("processing reconciliation error", "error", reconcileErr)
 
 		var graphErr *graphError
+		var conflictErr *kroclient.OwnerConflictError
 		var crdErr *crdError
 		var microControllerErr *microControllerError
 
 		switch {
 		case errors.As(reconcileErr, &graphErr):
 			processor.processGraphError(reconcileErr)
+		case errors.As(reconcileErr, &conflictErr):
+			processor.processKindConflict(resourcegroup.Spec.Schema.Kind, conflictErr)
 		case errors.As(reconcileErr, &crdErr):
 			processor.processCRDError(reconcileErr)
 		case errors.As(reconcileErr, &microControllerErr):
@@ -116,7 +172,7 @@ func (r *ResourceGroupReconciler) setResourceGroupStatus(
 		}
 	}
 
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Get fresh copy to avoid conflicts
 		current := &v1alpha1.ResourceGroup{}
 		if err := r.Get(ctx, client.ObjectKeyFromObject(resourcegroup), current); err != nil {
@@ -127,8 +183,14 @@ func (r *ResourceGroupReconciler) setResourceGroupStatus(
 		dc := current.DeepCopy()
 		dc.Status.Conditions = processor.conditions
 		dc.Status.State = processor.state
-		dc.Status.TopologicalOrder = topologicalOrder
+		dc.Status.TopologicalOrder = nil
+		dc.Status.TopologicalLevels = nil
+		if processedRG != nil {
+			dc.Status.TopologicalOrder = processedRG.TopologicalOrder
+			dc.Status.TopologicalLevels = processedRG.TopologicalLevels
+		}
 		dc.Status.Resources = resources
+		dc.Status.ValidationErrors = processor.validationErrors
 
 		log.V(1).Info("updating resource group status",
 			"state", dc.Status.State,
@@ -137,6 +199,37 @@ func (r *ResourceGroupReconciler) setResourceGroupStatus(
 
 		return r.Status().Patch(ctx, dc, client.MergeFrom(current))
 	})
+	if err != nil {
+		return err
+	}
+	resourceGroupStates.set(client.ObjectKeyFromObject(resourcegroup).String(), processor.state)
+	return nil
+}
+
+// setDeletionBlockedStatus reports the number of instances holding the deletion of
+// the resource group in its conditions.
+func (r *ResourceGroupReconciler) setDeletionBlockedStatus(ctx context.Context, resourcegroup *v1alpha1.ResourceGroup, remaining int) error {
+	condition := newInstancesCleanedUpCondition(metav1.ConditionFalse, fmt.Sprintf(
+		"%d instances of %s still exist, delete them or set the %s annotation to \"true\"",
+		remaining, resourcegroup.Spec.Schema.Kind, metadata.ForceDeletionAnnotation,
+	))
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1alpha1.ResourceGroup{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(resourcegroup), current); err != nil {
+			return fmt.Errorf("failed to get current resource group: %w", err)
+		}
+
+		dc := current.DeepCopy()
+		conditions := make([]v1alpha1.Condition, 0, len(dc.Status.Conditions)+1)
+		for _, c := range dc.Status.Conditions {
+			if c.Type != v1alpha1.ResourceGroupConditionTypeInstancesCleanedUp {
+				conditions = append(conditions, c)
+			}
+		}
+		dc.Status.Conditions = append(conditions, condition)
+		return r.Status().Patch(ctx, dc, client.MergeFrom(current))
+	})
 }
 
 // setManaged sets the resourcegroup as managed, by adding the
@@ -182,3 +275,15 @@ func newGraphVerifiedCondition(status metav1.ConditionStatus, reason string) v1a
 func newCustomResourceDefinitionSyncedCondition(status metav1.ConditionStatus, reason string) v1alpha1.Condition {
 	return v1alpha1.NewCondition(v1alpha1.ResourceGroupConditionTypeCustomResourceDefinitionSynced, status, reason, "Custom Resource Definition is synced")
 }
+
+func newKindClaimedCondition(status metav1.ConditionStatus, reason string) v1alpha1.Condition {
+	return v1alpha1.NewCondition(v1alpha1.ResourceGroupConditionTypeKindClaimed, status, reason, "Kind is claimed by the resource group")
+}
+
+func newInstancesCleanedUpCondition(status metav1.ConditionStatus, reason string) v1alpha1.Condition {
+	return v1alpha1.NewCondition(v1alpha1.ResourceGroupConditionTypeInstancesCleanedUp, status, reason, "Instances are deleted")
+}
+
+func newServiceAccountsVerifiedCondition(status metav1.ConditionStatus, reason string) v1alpha1.Condition {
+	return v1alpha1.NewCondition(v1alpha1.ResourceGroupConditionTypeServiceAccountsVerified, status, reason, "Default service accounts are allowed to manage the resources")
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resourcegroup
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+)
+
+const (
+	// MetricResourceGroups is the number of resource groups per state
+	MetricResourceGroups = "resource_group_count"
+	// MetricReconcileErrors is the total number of resource group
+	// reconciliation errors, by error class
+	MetricReconcileErrors = "resource_group_reconcile_errors_total"
+)
+
+// Classes of the resource group reconciliation errors.
+const (
+	errorClassGraph           = "graph"
+	errorClassKindConflict    = "kind_conflict"
+	errorClassCRD             = "crd"
+	errorClassMicroController = "micro_controller"
+	errorClassUnknown         = "unknown"
+)
+
+var (
+	resourceGroupCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricResourceGroups,
+			Help: "Number of resource groups by state",
+		},
+		[]string{"state"},
+	)
+
+	reconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricReconcileErrors,
+			Help: "Total number of resource group reconciliation errors by error class",
+		},
+		[]string{"error_class"},
+	)
+)
+
+// errorClass returns the class of a resource group reconciliation error.
+func errorClass(err error) string {
+	var graphErr *graphError
+	var conflictErr *kroclient.OwnerConflictError
+	var crdErr *crdError
+	var microControllerErr *microControllerError
+
+	switch {
+	case errors.As(err, &graphErr):
+		return errorClassGraph
+	case errors.As(err, &conflictErr):
+		return errorClassKindConflict
+	case errors.As(err, &crdErr):
+		return errorClassCRD
+	case errors.As(err, &microControllerErr):
+		return errorClassMicroController
+	default:
+		return errorClassUnknown
+	}
+}
+
+func recordReconcileErrorMetric(err error) {
+	reconcileErrorsTotal.WithLabelValues(errorClass(err)).Inc()
+}
+
+// resourceGroupStates keeps track of the last known state of the resource
+// groups, indexed by namespace/name, to maintain the resource group gauge.
+var resourceGroupStates = &stateTracker{
+	states: make(map[string]v1alpha1.ResourceGroupState),
+}
+
+type stateTracker struct {
+	mu     sync.Mutex
+	states map[string]v1alpha1.ResourceGroupState
+}
+
+// set records the state of a resource group.
+func (t *stateTracker) set(key string, state v1alpha1.ResourceGroupState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	previous, ok := t.states[key]
+	if ok && previous == state {
+		return
+	}
+	if ok {
+		resourceGroupCount.WithLabelValues(string(previous)).Dec()
+	}
+	resourceGroupCount.WithLabelValues(string(state)).Inc()
+	t.states[key] = state
+}
+
+// forget stops tracking a deleted resource group.
+func (t *stateTracker) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	previous, ok := t.states[key]
+	if !ok {
+		return
+	}
+	resourceGroupCount.WithLabelValues(string(previous)).Dec()
+	delete(t.states, key)
+}
+
+func init() {
+	metrics.Registry.MustRegister(
+		resourceGroupCount,
+		reconcileErrorsTotal,
+	)
+}
@@ -57,22 +57,21 @@ package dynamiccontroller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
-	"golang.org/x/time/rate"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/awslabs/kro/pkg/metadata"
@@ -81,8 +80,16 @@ import (
 
 // Config holds the configuration for DynamicController
 type Config struct {
-	// Workers specifies the number of workers processing items from the queue
+	// Workers specifies the number of workers processing items from the queue.
+	// When MaxWorkers is set, it is the minimum number of workers.
 	Workers int
+	// MaxWorkers is the maximum number of workers. When greater than Workers,
+	// the number of workers grows and shrinks between the two, based on the
+	// queue depth and the average reconcile latency.
+	MaxWorkers int
+	// WorkerScalingInterval is the interval at which the number of workers is
+	// re-evaluated. Defaults to 10 seconds.
+	WorkerScalingInterval time.Duration
 	// ResyncPeriod defines the interval at which the controller will re list
 	// the resources, even if there haven't been any changes.
 	ResyncPeriod time.Duration
@@ -96,6 +103,14 @@ type Config struct {
 	// gracefully shutdown. We ideally want to avoid forceful shutdowns, giving
 	// the controller enough time to finish processing any pending items.
 	ShutdownTimeout time.Duration
+	// Namespaces is the list of namespaces the controller watches instances
+	// in. All namespaces are watched when empty. Restricting the namespaces
+	// allows running kro with namespaced RBAC only.
+	Namespaces []string
+	// LabelSelector restricts the watched instances to the ones matching the
+	// selector. All instances are watched when empty. It allows several kro
+	// replicas to split the instances of a same GVR.
+	LabelSelector string
 }
 
 // DynamicController (DC) is a single controller capable of managing multiple different
@@ -122,8 +137,13 @@ type DynamicController struct {
 	// handler is responsible for managing a specific GVR.
 	handlers sync.Map
 
-	// queue is the workqueue used to process items
-	queue workqueue.RateLimitingInterface
+	// queues holds a workqueue per GVR, and dispatches their items to the
+	// workers using a weighted round-robin. A GVR with many pending items
+	// cannot starve the others.
+	queues *scheduler
+
+	// workers is the pool of workers processing items from the queues.
+	workers workerPool
 
 	log logr.Logger
 }
@@ -131,8 +151,9 @@ type DynamicController struct {
 type Handler func(ctx context.Context, req ctrl.Request) error
 
 type informerWrapper struct {
-	informer dynamicinformer.DynamicSharedInformerFactory
-	shutdown func()
+	// informers holds an informer factory per watched namespace.
+	informers []dynamicinformer.DynamicSharedInformerFactory
+	shutdown  func()
 }
 
 // NewDynamicController creates a new DynamicController instance.
@@ -146,12 +167,8 @@ func NewDynamicController(
 	dc := &DynamicController{
 		config:     config,
 		kubeClient: kubeClient,
-		// TODO(a-hilaly): Make the queue size configurable.
-		queue: workqueue.NewNamedRateLimitingQueue(workqueue.NewMaxOfRateLimiter(
-			workqueue.NewItemExponentialFailureRateLimiter(200*time.Millisecond, 1000*time.Second),
-			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
-		), "dynamic-controller-queue"),
-		log: logger,
+		queues:     newScheduler(),
+		log:        logger,
 		// pass version and pod id from env
 	}
 
@@ -203,7 +220,7 @@ func (dc *DynamicController) WaitForInformersSync(stopCh <-chan struct{}) bool {
 // Run starts the DynamicController.
 func (dc *DynamicController) Run(ctx context.Context) error {
 	defer utilruntime.HandleCrash()
-	defer dc.queue.ShutDown()
+	defer dc.queues.shutdown()
 
 	dc.log.Info("Starting dynamic controller")
 	defer dc.log.Info("Shutting down dynamic controller")
@@ -213,72 +230,81 @@ func (dc *DynamicController) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to sync informers")
 	}
 
-	// Spin up workers.
-	//
-	// TODO(a-hilaly): Allow for dynamic scaling of workers.
-	for i := 0; i < dc.config.Workers; i++ {
-		go wait.UntilWithContext(ctx, dc.worker, time.Second)
+	// Spin up workers, and scale them with the load if allowed.
+	dc.scaleWorkers(ctx, dc.config.Workers)
+	if dc.config.MaxWorkers > dc.config.Workers {
+		go dc.autoscaleWorkers(ctx)
 	}
 
 	<-ctx.Done()
 	return dc.gracefulShutdown(dc.config.ShutdownTimeout)
 }
 
-// worker processes items from the queue.
-func (dc *DynamicController) worker(ctx context.Context) {
-	for dc.processNextWorkItem(ctx) {
+// worker processes items from the queue, until stopCtx is done.
+func (dc *DynamicController) worker(ctx, stopCtx context.Context) {
+	for dc.processNextWorkItem(ctx, stopCtx) {
 	}
 }
 
-// processNextWorkItem processes a single item from the queue.
-func (dc *DynamicController) processNextWorkItem(ctx context.Context) bool {
-	obj, shutdown := dc.queue.Get()
-	if shutdown {
+// processNextWorkItem processes a single item from the GVR queues. It returns
+// false once stopCtx is done. Items are reconciled with ctx, so that stopping
+// a worker doesn't interrupt the item it is processing.
+func (dc *DynamicController) processNextWorkItem(ctx, stopCtx context.Context) bool {
+	q, obj, ok := dc.queues.next(stopCtx)
+	if !ok {
 		return false
 	}
-	defer dc.queue.Done(obj)
-
-	queueLength.Set(float64(dc.queue.Len()))
+	queue := q.queue
+	defer queue.Done(obj)
 
 	item, ok := obj.(ObjectIdentifiers)
 	if !ok {
 		dc.log.Error(fmt.Errorf("expected ObjectIdentifiers in queue but got %#v", obj), "Invalid item in queue")
-		dc.queue.Forget(obj)
+		queue.Forget(obj)
+		return true
+	}
+
+	// The GVR stopped being served while the item was waiting for a worker.
+	if queue.ShuttingDown() {
+		dc.log.V(1).Info("Discarding item of a GVR that is no longer served", "item", item)
+		queue.Forget(obj)
 		return true
 	}
 
+	queueLength.WithLabelValues(q.key).Set(float64(queue.Len()))
+
 	err := dc.syncFunc(ctx, item)
 	if err == nil || apierrors.IsNotFound(err) {
-		dc.queue.Forget(obj)
+		queue.Forget(obj)
 		return true
 	}
 
-	gvrKey := fmt.Sprintf("%s/%s/%s", item.GVR.Group, item.GVR.Version, item.GVR.Resource)
+	gvrKey := q.key
 
 	// Handle requeues
 	switch typedErr := err.(type) {
 	case *requeue.NoRequeue:
 		dc.log.Error(typedErr, "Error syncing item, not requeuing", "item", item)
 		requeueTotal.WithLabelValues(gvrKey, "no_requeue").Inc()
-		dc.queue.Forget(obj)
+		queue.Forget(obj)
 	case *requeue.RequeueNeeded:
 		dc.log.V(1).Info("Requeue needed", "item", item, "error", typedErr)
 		requeueTotal.WithLabelValues(gvrKey, "requeue").Inc()
-		dc.queue.Add(obj) // Add without rate limiting
+		queue.Add(obj) // Add without rate limiting
 	case *requeue.RequeueNeededAfter:
 		dc.log.V(1).Info("Requeue needed after delay", "item", item, "error", typedErr, "delay", typedErr.Duration())
 		requeueTotal.WithLabelValues(gvrKey, "requeue_after").Inc()
-		dc.queue.AddAfter(obj, typedErr.Duration())
+		queue.AddAfter(obj, typedErr.Duration())
 	default:
 		// Arriving here means we have an unexpected error, we should requeue the item
 		// with rate limiting.
 		requeueTotal.WithLabelValues(gvrKey, "rate_limited").Inc()
-		if dc.queue.NumRequeues(obj) < dc.config.QueueMaxRetries {
+		if queue.NumRequeues(obj) < dc.config.QueueMaxRetries {
 			dc.log.Error(err, "Error syncing item, requeuing with rate limit", "item", item)
-			dc.queue.AddRateLimited(obj)
+			queue.AddRateLimited(obj)
 		} else {
 			dc.log.Error(err, "Dropping item from queue after max retries", "item", item)
-			dc.queue.Forget(obj)
+			queue.Forget(obj)
 		}
 	}
 
@@ -314,12 +340,22 @@ func (dc *DynamicController) syncFunc(ctx context.Context, oi ObjectIdentifiers)
 		return fmt.Errorf("invalid handler type for GVR: %s", gvrKey)
 	}
 	err := handlerFunc(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: oi.NamespacedKey}})
-	if err != nil {
+	if isHandlerError(err) {
 		handlerErrorsTotal.WithLabelValues(gvrKey).Inc()
 	}
 	return err
 }
 
+// isHandlerError returns true if the handler failed. Requeues that don't wrap
+// an error, e.g the periodic requeues of healthy objects, aren't failures.
+func isHandlerError(err error) bool {
+	switch err.(type) {
+	case *requeue.NoRequeue, *requeue.RequeueNeeded, *requeue.RequeueNeededAfter:
+		return errors.Unwrap(err) != nil
+	}
+	return err != nil
+}
+
 // gracefulShutdown performs a graceful shutdown of the controller.
 func (dc *DynamicController) gracefulShutdown(timeout time.Duration) error {
 	dc.log.Info("Starting graceful shutdown")
@@ -332,7 +368,9 @@ func (dc *DynamicController) gracefulShutdown(timeout time.Duration) error {
 		wg.Add(1)
 		go func(informer *informerWrapper) {
 			defer wg.Done()
-			informer.informer.Shutdown()
+			for _, factory := range informer.informers {
+				factory.Shutdown()
+			}
 		}(value.(*informerWrapper))
 		return true
 	})
@@ -379,7 +417,10 @@ func (dc *DynamicController) updateFunc(old, new interface{}) {
 		return
 	}
 
-	if newObj.GetGeneration() == oldObj.GetGeneration() {
+	// Pausing or resuming an object doesn't change its generation, but must
+	// be reconciled.
+	if newObj.GetGeneration() == oldObj.GetGeneration() &&
+		metadata.IsPaused(newObj.GetAnnotations()) == metadata.IsPaused(oldObj.GetAnnotations()) {
 		dc.log.V(2).Info("Skipping update due to unchanged generation",
 			"name", newObj.GetName(),
 			"namespace", newObj.GetNamespace(),
@@ -418,78 +459,144 @@ func (dc *DynamicController) enqueueObject(obj interface{}, eventType string) {
 		"eventType", eventType)
 
 	informerEventsTotal.WithLabelValues(gvr.String(), eventType).Inc()
-	dc.queue.Add(objectIdentifiers)
+
+	q, ok := dc.queues.get(gvr)
+	if !ok {
+		dc.log.V(1).Info("Dropping object of a GVR that is not served",
+			"objectIdentifiers", objectIdentifiers,
+			"eventType", eventType)
+		return
+	}
+	q.queue.Add(objectIdentifiers)
+	queueLength.WithLabelValues(q.key).Set(float64(q.queue.Len()))
 }
 
 // StartServingGVK registers a new GVK to the informers map safely.
-func (dc *DynamicController) StartServingGVK(ctx context.Context, gvr schema.GroupVersionResource, handler Handler) error {
+func (dc *DynamicController) StartServingGVK(
+	ctx context.Context,
+	gvr schema.GroupVersionResource,
+	handler Handler,
+	opts ...GVROption,
+) error {
 	dc.log.V(1).Info("Registering new GVK", "gvr", gvr)
 
+	options := gvrOptions{queueWeight: defaultQueueWeight}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	_, exists := dc.informers.Load(gvr)
 	if exists {
 		// Even thought the informer is already registered, we should still
-		// still update the handler, as it might have changed.
+		// still update the handler and the queue weight, as they might have
+		// changed.
 		dc.handlers.Store(gvr, handler)
+		dc.queues.getOrAdd(gvr, options.queueWeight)
 		return nil
 	}
 
-	// Create a new informer
-	gvkInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
-		dc.kubeClient,
-		dc.config.ResyncPeriod,
-		// Maybe we can make this configurable in the future. Thinking that
-		// we might want to filter out some resources, by namespace or labels
-		"",
-		nil,
-	)
-
-	informer := gvkInformer.ForResource(gvr).Informer()
+	// The queue must exist before the informer starts sending events.
+	dc.queues.getOrAdd(gvr, options.queueWeight)
 
-	// Set up event handlers
-	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { dc.enqueueObject(obj, "add") },
-		UpdateFunc: dc.updateFunc,
-		DeleteFunc: func(obj interface{}) { dc.enqueueObject(obj, "delete") },
-	})
-	if err != nil {
-		dc.log.Error(err, "Failed to add event handler", "gvr", gvr)
-		return fmt.Errorf("failed to add event handler for GVR %s: %w", gvr, err)
+	// Create an informer per watched namespace. An empty namespace means all
+	// namespaces.
+	namespaces := dc.config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
 	}
-	informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
-		dc.log.Error(err, "Watch error", "gvr", gvr)
-	})
-	dc.handlers.Store(gvr, handler)
 
 	informerContext := context.Background()
 	cancelableContext, cancel := context.WithCancel(informerContext)
-	// Start the informer
-	go func() {
-		dc.log.V(1).Info("Starting informer", "gvr", gvr)
-		// time.Sleep(5 * time.Millisecond)
-		informer.Run(cancelableContext.Done())
-	}()
+
+	factories := make([]dynamicinformer.DynamicSharedInformerFactory, 0, len(namespaces))
+	hasSynced := make([]cache.InformerSynced, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		gvkInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+			dc.kubeClient,
+			dc.config.ResyncPeriod,
+			namespace,
+			dc.tweakListOptions,
+		)
+
+		informer := gvkInformer.ForResource(gvr).Informer()
+
+		// Set up event handlers
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { dc.enqueueObject(obj, "add") },
+			UpdateFunc: dc.updateFunc,
+			DeleteFunc: func(obj interface{}) { dc.enqueueObject(obj, "delete") },
+		})
+		if err != nil {
+			cancel()
+			dc.removeQueue(gvr)
+			dc.log.Error(err, "Failed to add event handler", "gvr", gvr)
+			return fmt.Errorf("failed to add event handler for GVR %s: %w", gvr, err)
+		}
+		informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+			dc.log.Error(err, "Watch error", "gvr", gvr, "namespace", namespace)
+		})
+
+		// Start the informer
+		go func() {
+			dc.log.V(1).Info("Starting informer", "gvr", gvr, "namespace", namespace)
+			informer.Run(cancelableContext.Done())
+		}()
+
+		factories = append(factories, gvkInformer)
+		hasSynced = append(hasSynced, informer.HasSynced)
+	}
+	dc.handlers.Store(gvr, handler)
 
 	dc.log.V(1).Info("Waiting for cache sync", "gvr", gvr)
 	startTime := time.Now()
 	// Wait for cache sync with a timeout
-	synced := cache.WaitForCacheSync(cancelableContext.Done(), informer.HasSynced)
+	synced := cache.WaitForCacheSync(cancelableContext.Done(), hasSynced...)
 	syncDuration := time.Since(startTime)
 	informerSyncDuration.WithLabelValues(gvr.String()).Observe(syncDuration.Seconds())
 
 	if !synced {
 		cancel()
+		dc.removeQueue(gvr)
 		return fmt.Errorf("failed to sync informer cache for GVR %s", gvr)
 	}
 
 	dc.informers.Store(gvr, &informerWrapper{
-		informer: gvkInformer,
-		shutdown: cancel,
+		informers: factories,
+		shutdown:  cancel,
 	})
 	gvrCount.Inc()
 	dc.log.V(1).Info("Successfully registered GVK", "gvr", gvr)
 	return nil
 }
 
+// Requeue enqueues all the objects of a served GVR, e.g when a change of their
+// handler needs to be applied to the objects that didn't change.
+func (dc *DynamicController) Requeue(gvr schema.GroupVersionResource) {
+	informerObj, ok := dc.informers.Load(gvr)
+	if !ok {
+		return
+	}
+	wrapper, ok := informerObj.(*informerWrapper)
+	if !ok {
+		return
+	}
+
+	dc.log.V(1).Info("Requeueing all objects", "gvr", gvr)
+	for _, factory := range wrapper.informers {
+		for _, obj := range factory.ForResource(gvr).Informer().GetStore().List() {
+			dc.enqueueObject(obj, "requeue")
+		}
+	}
+}
+
+// tweakListOptions restricts the informers list and watch requests to the
+// instances matching the configured label selector.
+func (dc *DynamicController) tweakListOptions(options *metav1.ListOptions) {
+	if dc.config.LabelSelector != "" {
+		options.LabelSelector = dc.config.LabelSelector
+	}
+}
+
 // UnregisterGVK safely removes a GVK from the controller and cleans up associated resources.
 func (dc *DynamicController) StopServiceGVK(ctx context.Context, gvr schema.GroupVersionResource) error {
 	dc.log.Info("Unregistering GVK", "gvr", gvr)
@@ -511,8 +618,10 @@ func (dc *DynamicController) StopServiceGVK(ctx context.Context, gvr schema.Grou
 
 	// Cancel the context to stop the informer
 	wrapper.shutdown()
-	// Wait for the informer to shut down
-	wrapper.informer.Shutdown()
+	// Wait for the informers to shut down
+	for _, factory := range wrapper.informers {
+		factory.Shutdown()
+	}
 
 	// Remove the informer from the map
 	dc.informers.Delete(gvr)
@@ -521,12 +630,19 @@ func (dc *DynamicController) StopServiceGVK(ctx context.Context, gvr schema.Grou
 	dc.handlers.Delete(gvr)
 
 	gvrCount.Dec()
-	// Clean up any pending items in the queue for this GVR
-	// NOTE(a-hilaly): This is a bit heavy.. maybe we can find a better way to do this.
-	// Thinking that we might want to have a queue per GVR.
-	// dc.cleanupQueue(gvr)
-	// time.Sleep(1 * time.Second)
-	// isStopped := wrapper.informer.ForResource(gvr).Informer().IsStopped()
+	// Drain and discard the pending items of this GVR.
+	dc.removeQueue(gvr)
 	dc.log.V(1).Info("Successfully unregistered GVK", "gvr", gvr)
 	return nil
 }
+
+// removeQueue removes the queue of a GVR, discarding its pending items.
+func (dc *DynamicController) removeQueue(gvr schema.GroupVersionResource) {
+	q, ok := dc.queues.get(gvr)
+	if !ok {
+		return
+	}
+	discarded := dc.queues.remove(gvr)
+	queueLength.DeleteLabelValues(q.key)
+	dc.log.V(1).Info("Removed GVR queue", "gvr", gvr, "discardedItems", discarded)
+}
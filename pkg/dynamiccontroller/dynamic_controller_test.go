@@ -14,6 +14,7 @@ package dynamiccontroller
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"testing"
 	"time"
@@ -27,6 +28,9 @@ import (
 	"k8s.io/client-go/dynamic/fake"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/awslabs/kro/pkg/metadata"
+	"github.com/awslabs/kro/pkg/requeue"
 )
 
 // NOTE(a-hilaly): I'm just playing around with the dynamic controller code here
@@ -69,7 +73,7 @@ func TestNewDynamicController(t *testing.T) {
 
 	assert.NotNil(t, dc)
 	assert.Equal(t, config, dc.config)
-	assert.NotNil(t, dc.queue)
+	assert.NotNil(t, dc.queues)
 	assert.NotNil(t, dc.kubeClient)
 }
 
@@ -109,10 +113,19 @@ func TestRegisterAndUnregisterGVK(t *testing.T) {
 
 	_, exists := dc.informers.Load(gvr)
 	assert.True(t, exists)
+	queue, ok := dc.queues.get(gvr)
+	require.True(t, ok)
+	assert.Equal(t, defaultQueueWeight, queue.weight)
+
+	// Registering again updates the queue weight
+	err = dc.StartServingGVK(context.Background(), gvr, handlerFunc, WithQueueWeight(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, queue.weight)
 
-	// Try to register again (should not fail)
+	// Try to register again (should not fail), the weight is reset
 	err = dc.StartServingGVK(context.Background(), gvr, handlerFunc)
 	assert.NoError(t, err)
+	assert.Equal(t, defaultQueueWeight, queue.weight)
 
 	// Unregister GVK
 	shutdownContext, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -134,7 +147,174 @@ func TestEnqueueObject(t *testing.T) {
 	obj.SetNamespace("default")
 	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"})
 
+	// Objects of GVRs that aren't served are dropped.
 	dc.enqueueObject(obj, "add")
+	_, ok := dc.queues.get(schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"})
+	assert.False(t, ok)
+
+	q := dc.queues.getOrAdd(schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}, 1)
+	dc.enqueueObject(obj, "add")
+
+	assert.Equal(t, 1, q.queue.Len())
+}
+
+func TestUpdateFunc(t *testing.T) {
+	newObject := func(generation int64, annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetName("test-object")
+		obj.SetNamespace("default")
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"})
+		obj.SetGeneration(generation)
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+	paused := map[string]string{metadata.PausedAnnotation: "true"}
+
+	tests := []struct {
+		name        string
+		old         *unstructured.Unstructured
+		new         *unstructured.Unstructured
+		wantEnqueue bool
+	}{
+		{
+			name: "unchanged generation",
+			old:  newObject(1, nil),
+			new:  newObject(1, map[string]string{"foo": "bar"}),
+		},
+		{
+			name:        "changed generation",
+			old:         newObject(1, nil),
+			new:         newObject(2, nil),
+			wantEnqueue: true,
+		},
+		{
+			name:        "paused",
+			old:         newObject(1, nil),
+			new:         newObject(1, paused),
+			wantEnqueue: true,
+		},
+		{
+			name:        "resumed",
+			old:         newObject(1, paused),
+			new:         newObject(1, nil),
+			wantEnqueue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := NewDynamicController(noopLogger(), Config{}, setupFakeClient())
+			q := dc.queues.getOrAdd(schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}, 1)
+
+			dc.updateFunc(tt.old, tt.new)
+			assert.Equal(t, tt.wantEnqueue, q.queue.Len() == 1)
+		})
+	}
+}
+
+func TestIsHandlerError(t *testing.T) {
+	err := fmt.Errorf("failed")
+	assert.False(t, isHandlerError(nil))
+	assert.True(t, isHandlerError(err))
+	assert.True(t, isHandlerError(requeue.NeededAfter(err, time.Second)))
+	assert.False(t, isHandlerError(requeue.NeededAfter(nil, time.Second)))
+	assert.False(t, isHandlerError(requeue.Needed(nil)))
+	assert.True(t, isHandlerError(requeue.None(err)))
+}
+
+func TestSchedulerWeightedRoundRobin(t *testing.T) {
+	s := newScheduler()
+	noisy := s.getOrAdd(schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "noisy"}, 2)
+	quiet := s.getOrAdd(schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "quiet"}, 1)
+
+	for i := 0; i < 6; i++ {
+		noisy.queue.Add(fmt.Sprintf("noisy-%d", i))
+	}
+	for i := 0; i < 2; i++ {
+		quiet.queue.Add(fmt.Sprintf("quiet-%d", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	for i := 0; i < 8; i++ {
+		q, item, ok := s.next(ctx)
+		require.True(t, ok)
+		q.queue.Done(item)
+		got = append(got, item.(string))
+	}
+
+	assert.Equal(t, []string{
+		"noisy-0", "noisy-1", "quiet-0",
+		"noisy-2", "noisy-3", "quiet-1",
+		"noisy-4", "noisy-5",
+	}, got)
+}
+
+func TestSchedulerRemoveDiscardsPendingItems(t *testing.T) {
+	s := newScheduler()
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	q := s.getOrAdd(gvr, 1)
+	q.queue.Add("a")
+	q.queue.Add("b")
+
+	assert.Equal(t, 2, s.remove(gvr))
+	assert.True(t, q.queue.ShuttingDown())
+	_, ok := s.get(gvr)
+	assert.False(t, ok)
+
+	// Items added after the removal are ignored.
+	q.queue.Add("c")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, ok = s.next(ctx)
+	assert.False(t, ok)
+}
+
+func TestStartServingGVKWithFilters(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	newObject := func(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"})
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(labels)
+		return obj
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gvr: "TestList",
+	},
+		newObject("team-a", "sharded", map[string]string{"shard": "0"}),
+		newObject("team-a", "other-shard", map[string]string{"shard": "1"}),
+		newObject("team-b", "sharded", map[string]string{"shard": "0"}),
+		newObject("team-c", "sharded", map[string]string{"shard": "0"}),
+	)
+
+	dc := NewDynamicController(noopLogger(), Config{
+		ResyncPeriod:  10 * time.Hour,
+		Namespaces:    []string{"team-a", "team-b"},
+		LabelSelector: "shard=0",
+	}, client)
+
+	handlerFunc := Handler(func(ctx context.Context, req controllerruntime.Request) error {
+		return nil
+	})
+	err := dc.StartServingGVK(context.Background(), gvr, handlerFunc)
+	require.NoError(t, err)
+	defer func() {
+		_ = dc.StopServiceGVK(context.Background(), gvr)
+	}()
+
+	q, ok := dc.queues.get(gvr)
+	require.True(t, ok)
 
-	assert.Equal(t, 1, dc.queue.Len())
+	var keys []string
+	for q.queue.Len() > 0 {
+		item, _ := q.queue.Get()
+		keys = append(keys, item.(ObjectIdentifiers).NamespacedKey)
+		q.queue.Done(item)
+	}
+	assert.ElementsMatch(t, []string{"team-a/sharded", "team-b/sharded"}, keys)
 }
@@ -30,7 +30,7 @@ func init() {
 		handlerErrorsTotal,
 		informerSyncDuration,
 		informerEventsTotal,
-		// activeWorkersTotal,
+		activeWorkersTotal,
 	)
 }
 
@@ -65,11 +65,12 @@ var (
 			Help: "Number of GVRs currently managed by the controller",
 		},
 	)
-	queueLength = prometheus.NewGauge(
+	queueLength = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "dynamic_controller_queue_length",
-			Help: "Current length of the workqueue",
+			Help: "Current length of the workqueue per GVR",
 		},
+		[]string{"gvr"},
 	)
 	handlerErrorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -93,11 +94,10 @@ var (
 		},
 		[]string{"gvr", "event_type"},
 	)
-	/* activeWorkersTotal = prometheus.NewGauge(
+	activeWorkersTotal = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "dynamic_controller_active_workers_total",
 			Help: "Total number of currently active workers",
 		},
 	)
-	*/
 )
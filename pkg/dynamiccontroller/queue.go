@@ -0,0 +1,281 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// defaultQueueWeight is the weight of a GVR queue when none is provided.
+const defaultQueueWeight = 1
+
+// GVROption configures how a GVR is served by the DynamicController.
+type GVROption func(*gvrOptions)
+
+type gvrOptions struct {
+	queueWeight int
+}
+
+// WithQueueWeight sets the weight of the GVR queue. When several GVRs have
+// pending items, a queue with a weight of N gets up to N items dispatched to
+// the workers before the next queue is served.
+func WithQueueWeight(weight int) GVROption {
+	return func(o *gvrOptions) {
+		if weight > 0 {
+			o.queueWeight = weight
+		}
+	}
+}
+
+// gvrQueue is the work queue of a single GVR.
+type gvrQueue struct {
+	gvr    schema.GroupVersionResource
+	key    string
+	weight int
+	queue  workqueue.RateLimitingInterface
+}
+
+// notifyingQueue is a workqueue.Interface calling notify every time an item
+// might have become available. Delayed and rate limited items are added to
+// it by the delaying queue once they're ready, and items re-added while being
+// processed become available once they're done. Both need to wake up idle
+// workers.
+type notifyingQueue struct {
+	workqueue.Interface
+	notify func()
+}
+
+func (q *notifyingQueue) Add(item interface{}) {
+	q.Interface.Add(item)
+	q.notify()
+}
+
+func (q *notifyingQueue) Done(item interface{}) {
+	q.Interface.Done(item)
+	q.notify()
+}
+
+// newGVRQueue creates a new rate limited queue for the given GVR. notify is
+// called every time an item might have become available in the queue.
+func newGVRQueue(gvr schema.GroupVersionResource, weight int, notify func()) *gvrQueue {
+	key := fmt.Sprintf("%s/%s/%s", gvr.Group, gvr.Version, gvr.Resource)
+	name := "dynamic-controller-queue-" + key
+	return &gvrQueue{
+		gvr:    gvr,
+		key:    key,
+		weight: weight,
+		// TODO(a-hilaly): Make the queue size configurable.
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.NewMaxOfRateLimiter(
+				workqueue.NewItemExponentialFailureRateLimiter(200*time.Millisecond, 1000*time.Second),
+				&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+			),
+			workqueue.RateLimitingQueueConfig{
+				Name: name,
+				DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+					Name: name,
+					Queue: &notifyingQueue{
+						Interface: workqueue.NewWithConfig(workqueue.QueueConfig{Name: name}),
+						notify:    notify,
+					},
+				}),
+			},
+		),
+	}
+}
+
+// scheduler dispatches the items of the GVR queues to the workers using a
+// weighted round-robin. It serves up to weight items from a queue before
+// moving to the next one, so that a GVR with thousands of pending items
+// cannot starve the others.
+type scheduler struct {
+	mu sync.Mutex
+	// queues is the list of queues, sorted by GVR to keep the round-robin
+	// order deterministic.
+	queues []*gvrQueue
+	// cursor is the index of the queue currently being served.
+	cursor int
+	// credit is the number of items the current queue can still get
+	// dispatched before moving to the next queue.
+	credit int
+	// ready is signaled every time an item might have become available.
+	ready chan struct{}
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// signal wakes up a worker waiting for an item, if any.
+func (s *scheduler) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// get returns the queue of the given GVR.
+func (s *scheduler) get(gvr schema.GroupVersionResource) (*gvrQueue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.queues {
+		if q.gvr == gvr {
+			return q, true
+		}
+	}
+	return nil, false
+}
+
+// getOrAdd returns the queue of the given GVR, creating it if needed. The
+// weight of an existing queue is updated.
+func (s *scheduler) getOrAdd(gvr schema.GroupVersionResource, weight int) *gvrQueue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.queues {
+		if q.gvr == gvr {
+			q.weight = weight
+			return q
+		}
+	}
+
+	var current *gvrQueue
+	if len(s.queues) > 0 {
+		current = s.queues[s.cursor]
+	}
+
+	q := newGVRQueue(gvr, weight, s.signal)
+	s.queues = append(s.queues, q)
+	sort.Slice(s.queues, func(i, j int) bool {
+		return s.queues[i].key < s.queues[j].key
+	})
+
+	// Keep serving the same queue.
+	for i := range s.queues {
+		if s.queues[i] == current {
+			s.cursor = i
+			break
+		}
+	}
+	return q
+}
+
+// remove removes the queue of the given GVR from the round-robin, shuts it
+// down and discards its pending items. It returns the number of discarded
+// items.
+func (s *scheduler) remove(gvr schema.GroupVersionResource) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, q := range s.queues {
+		if q.gvr != gvr {
+			continue
+		}
+		s.queues = append(s.queues[:i], s.queues[i+1:]...)
+		switch {
+		case i < s.cursor:
+			s.cursor--
+		case i == s.cursor:
+			s.credit = 0
+		}
+		if s.cursor >= len(s.queues) {
+			s.cursor = 0
+		}
+
+		// Nothing will call Get on the queue anymore, the pending items are
+		// dropped with it. Items being processed are discarded by the
+		// workers once they're done.
+		discarded := q.queue.Len()
+		q.queue.ShutDown()
+		return discarded
+	}
+	return 0
+}
+
+// len returns the number of items ready to be processed across all queues.
+func (s *scheduler) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int
+	for _, q := range s.queues {
+		n += q.queue.Len()
+	}
+	return n
+}
+
+// next blocks until an item is available, and returns it along with the
+// queue it belongs to. It returns false when the context is done.
+func (s *scheduler) next(ctx context.Context) (*gvrQueue, interface{}, bool) {
+	for {
+		if q, item, ok := s.tryNext(); ok {
+			// There might be more items available, let another worker check.
+			s.signal()
+			return q, item, true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, false
+		case <-s.ready:
+		}
+	}
+}
+
+// tryNext returns the next item to process, following the weighted
+// round-robin order, without blocking.
+func (s *scheduler) tryNext() (*gvrQueue, interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for visited := 0; visited <= len(s.queues); visited++ {
+		if len(s.queues) == 0 {
+			return nil, nil, false
+		}
+		q := s.queues[s.cursor]
+		if s.credit == 0 {
+			s.credit = q.weight
+		}
+		// Len only counts the items that are ready to be processed, and
+		// nothing else calls Get, so Get never blocks here.
+		if q.queue.Len() > 0 {
+			item, shutdown := q.queue.Get()
+			if !shutdown {
+				s.credit--
+				if s.credit == 0 {
+					s.cursor = (s.cursor + 1) % len(s.queues)
+				}
+				return q, item, true
+			}
+		}
+		s.credit = 0
+		s.cursor = (s.cursor + 1) % len(s.queues)
+	}
+	return nil, nil, false
+}
+
+// shutdown shuts down all the queues.
+func (s *scheduler) shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.queues {
+		q.queue.ShutDown()
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// defaultWorkerScalingInterval is the interval at which the number of workers
+// is re-evaluated when none is configured.
+const defaultWorkerScalingInterval = 10 * time.Second
+
+// workerPool keeps track of the running workers, so that they can be
+// stopped one by one when scaling down.
+type workerPool struct {
+	mu sync.Mutex
+	// stops holds the function stopping each running worker.
+	stops []context.CancelFunc
+}
+
+// size returns the number of running workers.
+func (p *workerPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.stops)
+}
+
+// scaleWorkers starts or stops workers until n workers are running. Stopped
+// workers finish the item they're processing before exiting.
+func (dc *DynamicController) scaleWorkers(ctx context.Context, n int) {
+	dc.workers.mu.Lock()
+	defer dc.workers.mu.Unlock()
+
+	for len(dc.workers.stops) < n {
+		stopCtx, stop := context.WithCancel(ctx)
+		dc.workers.stops = append(dc.workers.stops, stop)
+		go dc.worker(ctx, stopCtx)
+	}
+	for len(dc.workers.stops) > n {
+		last := len(dc.workers.stops) - 1
+		dc.workers.stops[last]()
+		dc.workers.stops = dc.workers.stops[:last]
+	}
+	activeWorkersTotal.Set(float64(len(dc.workers.stops)))
+}
+
+// autoscaleWorkers periodically adjusts the number of workers between
+// Config.Workers and Config.MaxWorkers, based on the depth of the queues and
+// the average reconcile latency observed since the last evaluation.
+func (dc *DynamicController) autoscaleWorkers(ctx context.Context) {
+	interval := dc.config.WorkerScalingInterval
+	if interval <= 0 {
+		interval = defaultWorkerScalingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCount, lastSum := observedReconciles()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		count, sum := observedReconciles()
+		var latency time.Duration
+		if count > lastCount {
+			latency = time.Duration((sum - lastSum) / float64(count-lastCount) * float64(time.Second))
+		}
+		lastCount, lastSum = count, sum
+
+		current := dc.workers.size()
+		depth := dc.queues.len()
+		desired := desiredWorkers(current, dc.config.Workers, dc.config.MaxWorkers, depth, latency, interval)
+		if desired != current {
+			dc.log.V(1).Info("Scaling workers",
+				"from", current,
+				"to", desired,
+				"queueDepth", depth,
+				"averageLatency", latency)
+			dc.scaleWorkers(ctx, desired)
+		}
+	}
+}
+
+// desiredWorkers returns the number of workers needed to drain the queues
+// within an interval, given the average reconcile latency. It scales up right
+// away, but only scales down one worker at a time to avoid flapping.
+func desiredWorkers(current, minWorkers, maxWorkers, depth int, latency, interval time.Duration) int {
+	desired := current
+	switch {
+	case depth == 0:
+		desired = current - 1
+	case latency > 0:
+		desired = int(math.Ceil(float64(depth) * latency.Seconds() / interval.Seconds()))
+	case depth > current:
+		// No reconcile finished since the last evaluation, all the workers
+		// are likely busy.
+		desired = current + 1
+	}
+
+	if desired < current-1 {
+		desired = current - 1
+	}
+	if desired < minWorkers {
+		desired = minWorkers
+	}
+	if desired > maxWorkers {
+		desired = maxWorkers
+	}
+	return desired
+}
+
+// observedReconciles returns the number of reconciles and their total
+// duration in seconds, as observed by the reconcile duration metric.
+func observedReconciles() (uint64, float64) {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		reconcileDuration.Collect(metrics)
+		close(metrics)
+	}()
+
+	var count uint64
+	var sum float64
+	for metric := range metrics {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil || m.Histogram == nil {
+			continue
+		}
+		count += m.Histogram.GetSampleCount()
+		sum += m.Histogram.GetSampleSum()
+	}
+	return count, sum
+}
@@ -0,0 +1,101 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int
+		depth    int
+		latency  time.Duration
+		expected int
+	}{
+		{
+			name:     "idle queues scale down one worker",
+			current:  5,
+			depth:    0,
+			expected: 4,
+		},
+		{
+			name:     "never below the minimum",
+			current:  2,
+			depth:    0,
+			expected: 2,
+		},
+		{
+			name:     "scale up to drain the queues within the interval",
+			current:  2,
+			depth:    100,
+			latency:  500 * time.Millisecond,
+			expected: 5,
+		},
+		{
+			name:     "never above the maximum",
+			current:  2,
+			depth:    10000,
+			latency:  time.Second,
+			expected: 20,
+		},
+		{
+			name:     "scale down one worker at a time",
+			current:  10,
+			depth:    1,
+			latency:  100 * time.Millisecond,
+			expected: 9,
+		},
+		{
+			name:     "scale up by one when no reconcile finished",
+			current:  3,
+			depth:    50,
+			expected: 4,
+		},
+		{
+			name:     "keep workers busy with a short queue",
+			current:  3,
+			depth:    2,
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := desiredWorkers(tt.current, 2, 20, tt.depth, tt.latency, 10*time.Second)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestScaleWorkers(t *testing.T) {
+	dc := NewDynamicController(noopLogger(), Config{}, setupFakeClient())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dc.scaleWorkers(ctx, 4)
+	assert.Equal(t, 4, dc.workers.size())
+	assert.Equal(t, float64(4), testutil.ToFloat64(activeWorkersTotal))
+
+	dc.scaleWorkers(ctx, 1)
+	assert.Equal(t, 1, dc.workers.size())
+	assert.Equal(t, float64(1), testutil.ToFloat64(activeWorkersTotal))
+}
@@ -14,13 +14,17 @@
 package graph
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	cel "github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types/ref"
 	"golang.org/x/exp/maps"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -39,11 +43,14 @@ import (
 	"github.com/awslabs/kro/pkg/graph/variable"
 	"github.com/awslabs/kro/pkg/metadata"
 	"github.com/awslabs/kro/pkg/simpleschema"
+	"github.com/awslabs/kro/pkg/tracing"
 )
 
-// NewBuilder creates a new GraphBuilder instance.
+// NewBuilder creates a new GraphBuilder instance. Expressions whose estimated
+// cost is above the given CEL limits are rejected.
 func NewBuilder(
 	clientConfig *rest.Config,
+	celLimits krocel.Limits,
 ) (*Builder, error) {
 	schemaResolver, dc, err := schema.NewCombinedResolver(clientConfig)
 	if err != nil {
@@ -56,6 +63,7 @@ func NewBuilder(
 		resourceEmulator: resourceEmulator,
 		schemaResolver:   schemaResolver,
 		discoveryClient:  dc,
+		celLimits:        celLimits,
 	}
 	return rgBuilder, nil
 }
@@ -94,13 +102,23 @@ type Builder struct {
 	// validate the CEL expressions. To revisit.
 	resourceEmulator *emulator.Emulator
 	discoveryClient  discovery.DiscoveryInterface
+	// celLimits bounds the cost of the expressions defined in a resource group.
+	// Expressions are user authored and evaluated inside the controller, so we
+	// reject the ones that could stall a worker before they are ever evaluated.
+	celLimits krocel.Limits
 }
 
 // NewResourceGroup creates a new ResourceGroup object from the given ResourceGroup
 // CRD. The ResourceGroup object is a fully processed and validated representation
 // of the resource group CRD, it's underlying resources, and the relationships between
 // the resources.
-func (b *Builder) NewResourceGroup(originalCR *v1alpha1.ResourceGroup) (*Graph, error) {
+//
+// Each phase of the build is traced as a child span of the span in ctx, and
+// timed in the graph build duration histogram.
+func (b *Builder) NewResourceGroup(ctx context.Context, originalCR *v1alpha1.ResourceGroup) (_ *Graph, err error) {
+	ctx, end := startBuildPhase(ctx, buildPhaseTotal, tracing.ResourceGroupNameKey.String(originalCR.Name))
+	defer func() { end(err) }()
+
 	// Before anything else, let's copy the resource group to avoid modifying the
 	// original object.
 	rg := originalCR.DeepCopy()
@@ -112,9 +130,20 @@ func (b *Builder) NewResourceGroup(originalCR *v1alpha1.ResourceGroup) (*Graph,
 	//    that the names of the resources are valid to be used in CEL expressions.
 	//    for example name-something-something is not a valid name for a resource,
 	//    because in CEL - is a subtraction operator.
-	err := validateResourceGroupNamingConventions(rg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to validate resourcegroup: %w", err)
+	//
+	// Errors don't stop the build, they are all collected and reported at once.
+	// The resources that failed a stage are skipped by the following ones,
+	// along with the expressions referring to them, whose errors would only
+	// repeat theirs.
+	var errs ValidationErrors
+	var skipped []string
+	if err := validateResourceGroupNamingConventions(rg); err != nil {
+		errs.add("", err)
+		for _, namingErr := range errs {
+			if namingErr.ResourceID != "" && !slices.Contains(skipped, namingErr.ResourceID) {
+				skipped = append(skipped, namingErr.ResourceID)
+			}
+		}
 	}
 
 	// Now that we did a basic validation of the resource group, we can start understanding
@@ -128,24 +157,24 @@ func (b *Builder) NewResourceGroup(originalCR *v1alpha1.ResourceGroup) (*Graph,
 	//    CEL expressions.
 	// 4. Extract the CEL expressions from the resource + validate them.
 
-	namespacedResources := map[k8sschema.GroupVersionKind]bool{}
-	apiResourceList, err := b.discoveryClient.ServerPreferredNamespacedResources()
+	namespacedResources, err := b.discoverNamespacedResources(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve Kubernetes namespaced resources: %w", err)
-	}
-	for _, resourceList := range apiResourceList {
-		for _, r := range resourceList.APIResources {
-			gvk := k8sschema.FromAPIVersionAndKind(resourceList.GroupVersion, r.Kind)
-			namespacedResources[gvk] = r.Namespaced
-		}
+		return nil, err
 	}
 
 	// we'll also store the resources in a map for easy access later.
+	//
+	// A failing resource doesn't stop us from building the others.
 	resources := make(map[string]*Resource)
 	for _, rgResource := range rg.Spec.Resources {
-		r, err := b.buildRGResource(rgResource, namespacedResources)
+		if slices.Contains(skipped, rgResource.ID) {
+			continue
+		}
+		r, err := b.buildRGResource(ctx, rgResource, namespacedResources)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build resource '%v': %v", rgResource.ID, err)
+			errs.add(rgResource.ID, err)
+			skipped = append(skipped, rgResource.ID)
+			continue
 		}
 		resources[rgResource.ID] = r
 	}
@@ -190,24 +219,37 @@ func (b *Builder) NewResourceGroup(originalCR *v1alpha1.ResourceGroup) (*Graph,
 	// 4. Infer the status schema based on the CEL expressions.
 
 	instance, err := b.buildInstanceResource(
+		ctx,
 		rg.Spec.Schema.APIVersion,
 		rg.Spec.Schema.Kind,
 		rg.Spec.Schema,
 		// We need to pass the resources to the instance resource, so we can validate
 		// the CEL expressions in the context of the resources.
 		resources,
+		skipped,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build resourcegroup '%v': %w", rg.Name, err)
+		// Every expression can refer to the instance spec, they can't be
+		// validated without it.
+		errs.add("", newValidationError("", "spec.schema", "", fmt.Errorf("failed to build resourcegroup '%v': %w", rg.Name, err)))
+		return nil, errs
 	}
 
 	// Before getting into the dependency graph, we need to validate the CEL expressions
 	// in the instance resource. In order to do that, we need to isolate each resource
 	// and evaluate the CEL expressions in the context of the resource group. This is done
 	// by dry-running the CEL expressions against the emulated resources.
-	err = validateResourceCELExpressions(resources, instance)
+	_, endCELDryRun := startBuildPhase(ctx, buildPhaseCELDryRun)
+	err = validateResourceCELExpressions(ctx, resources, skipped, instance, b.celLimits)
+	endCELDryRun(err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate resource CEL expressions: %w", err)
+		errs.add("", err)
+	}
+
+	// The dependency graph is only built once every resource is valid, an
+	// incomplete graph would order the resources wrongly.
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	// Now that we have the instance resource, we can move into the next stage of
@@ -222,54 +264,106 @@ func (b *Builder) NewResourceGroup(originalCR *v1alpha1.ResourceGroup) (*Graph,
 	// The dependency graph is built by inspecting the CEL expressions in the
 	// resources and the instance resource, using a CEL AST (Abstract Syntax Tree)
 	// inspector.
-	dag, err := b.buildDependencyGraph(resources)
+	dag, topologicalLevels, err := b.buildDAG(ctx, resources)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+		return nil, err
 	}
-
-	topologicalOrder, err := dag.TopologicalSort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get topological order: %w", err)
+	topologicalOrder := make([]string, 0, len(resources))
+	for _, level := range topologicalLevels {
+		topologicalOrder = append(topologicalOrder, level...)
 	}
 
 	resourceGroup := &Graph{
-		DAG:              dag,
-		Instance:         instance,
-		Resources:        resources,
-		TopologicalOrder: topologicalOrder,
+		DAG:               dag,
+		Instance:          instance,
+		Resources:         resources,
+		TopologicalOrder:  topologicalOrder,
+		TopologicalLevels: topologicalLevels,
+		CELLimits:         b.celLimits,
 	}
 	return resourceGroup, nil
 }
 
+// discoverNamespacedResources returns whether each resource served by the
+// API server is namespaced, indexed by GVK.
+func (b *Builder) discoverNamespacedResources(ctx context.Context) (_ map[k8sschema.GroupVersionKind]bool, err error) {
+	_, end := startBuildPhase(ctx, buildPhaseDiscovery)
+	defer func() { end(err) }()
+
+	namespacedResources := map[k8sschema.GroupVersionKind]bool{}
+	apiResourceList, err := b.discoveryClient.ServerPreferredNamespacedResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve Kubernetes namespaced resources: %w", err)
+	}
+	for _, resourceList := range apiResourceList {
+		for _, r := range resourceList.APIResources {
+			gvk := k8sschema.FromAPIVersionAndKind(resourceList.GroupVersion, r.Kind)
+			namespacedResources[gvk] = r.Namespaced
+		}
+	}
+	return namespacedResources, nil
+}
+
+// buildDAG builds the dependency graph of the resources and computes its
+// topological levels.
+func (b *Builder) buildDAG(ctx context.Context, resources map[string]*Resource) (_ *dag.DirectedAcyclicGraph, _ [][]string, err error) {
+	_, end := startBuildPhase(ctx, buildPhaseDAG)
+	defer func() { end(err) }()
+
+	dag, err := b.buildDependencyGraph(resources)
+	if err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, nil, ValidationErrors{validationErr}
+		}
+		return nil, nil, ValidationErrors{newValidationError("", "", "", fmt.Errorf("failed to build dependency graph: %w", err))}
+	}
+
+	topologicalLevels, err := dag.TopologicalLevels()
+	if err != nil {
+		return nil, nil, ValidationErrors{newValidationError("", "", "", fmt.Errorf("failed to get topological order: %w", err))}
+	}
+	return dag, topologicalLevels, nil
+}
+
 // buildRGResource builds a resource from the given resource definition.
 // It provides a high-level understanding of the resource, by extracting the
 // OpenAPI schema, emualting the resource and extracting the cel expressions
 // from the schema.
-func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResources map[k8sschema.GroupVersionKind]bool) (*Resource, error) {
+func (b *Builder) buildRGResource(
+	ctx context.Context,
+	rgResource *v1alpha1.Resource,
+	namespacedResources map[k8sschema.GroupVersionKind]bool,
+) (*Resource, error) {
 	// 1. We need to unmashal the resource into a map[string]interface{} to
 	//    make it easier to work with.
 	resourceObject := map[string]interface{}{}
 	err := yaml.UnmarshalStrict(rgResource.Template.Raw, &resourceObject)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal resource %s: %w", rgResource.ID, err)
+		return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to unmarshal resource %s: %w", rgResource.ID, err))
 	}
 
 	// 1. Check if it looks like a valid Kubernetes resource.
 	err = validateKubernetesObjectStructure(resourceObject)
 	if err != nil {
-		return nil, fmt.Errorf("resource %s is not a valid Kubernetes object: %v", rgResource.ID, err)
+		return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("resource %s is not a valid Kubernetes object: %v", rgResource.ID, err))
 	}
 
 	// 2. Based the GVK, we need to load the OpenAPI schema for the resource.
 	gvk, err := metadata.ExtractGVKFromUnstructured(resourceObject)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract GVK from resource %s: %w", rgResource.ID, err)
+		return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to extract GVK from resource %s: %w", rgResource.ID, err))
 	}
 
 	// 3. Load the OpenAPI schema for the resource.
+	_, endSchemaResolution := startBuildPhase(ctx, buildPhaseSchemaResolution,
+		tracing.ResourceIDKey.String(rgResource.ID),
+		tracing.ResourceGVRKey.String(gvk.String()),
+	)
 	resourceSchema, err := b.schemaResolver.ResolveSchema(gvk)
+	endSchemaResolution(err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema for resource %s: %w", rgResource.ID, err)
+		return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to get schema for resource %s: %w", rgResource.ID, err))
 	}
 
 	var emulatedResource *unstructured.Unstructured
@@ -280,24 +374,26 @@ func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResou
 	if gvk.Group == "apiextensions.k8s.io" && gvk.Version == "v1" && gvk.Kind == "CustomResourceDefinition" {
 		celExpressions, err := parser.ParseSchemalessResource(resourceObject)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse schemaless resource %s: %w", rgResource.ID, err)
+			return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to parse schemaless resource %s: %w", rgResource.ID, err))
 		}
 		if len(celExpressions) > 0 {
-			return nil, fmt.Errorf("failed, CEL expressions are not supported for CRDs, resource %s", rgResource.ID)
+			return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed, CEL expressions are not supported for CRDs, resource %s", rgResource.ID))
 		}
 	} else {
 
 		// 4. Emulate the resource, this is later used to verify the validity of the
 		//    CEL expressions.
+		_, endEmulation := startBuildPhase(ctx, buildPhaseEmulation, tracing.ResourceIDKey.String(rgResource.ID))
 		emulatedResource, err = b.resourceEmulator.GenerateDummyCR(gvk, resourceSchema)
+		endEmulation(err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate dummy CR for resource %s: %w", rgResource.ID, err)
+			return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to generate dummy CR for resource %s: %w", rgResource.ID, err))
 		}
 
 		// 5. Extract CEL fieldDescriptors from the schema.
 		fieldDescriptors, err := parser.ParseResource(resourceObject, resourceSchema)
 		if err != nil {
-			return nil, fmt.Errorf("failed to extract CEL expressions from schema for resource %s: %w", rgResource.ID, err)
+			return nil, newValidationError(rgResource.ID, "template", "", fmt.Errorf("failed to extract CEL expressions from schema for resource %s: %w", rgResource.ID, err))
 		}
 		for _, fieldDescriptor := range fieldDescriptors {
 			resourceVariables = append(resourceVariables, &variable.ResourceField{
@@ -311,13 +407,33 @@ func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResou
 	// 6. Parse ReadyWhen expressions
 	readyWhen, err := parser.ParseConditionExpressions(rgResource.ReadyWhen)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse readyWhen expressions: %v", err)
+		return nil, newValidationError(rgResource.ID, "readyWhen", "", fmt.Errorf("failed to parse readyWhen expressions: %v", err))
 	}
 
 	// 7. Parse condition expressions
 	includeWhen, err := parser.ParseConditionExpressions(rgResource.IncludeWhen)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse includeWhen expressions: %v", err)
+		return nil, newValidationError(rgResource.ID, "includeWhen", "", fmt.Errorf("failed to parse includeWhen expressions: %v", err))
+	}
+
+	// 8. Validate the readiness policy
+	if err := validateReadinessPolicy(rgResource.ReadinessPolicy); err != nil {
+		return nil, newValidationError(rgResource.ID, "readinessPolicy", "", err)
+	}
+
+	// 9. Parse FailWhen expressions
+	failWhen, err := parser.ParseConditionExpressions(rgResource.FailWhen)
+	if err != nil {
+		return nil, newValidationError(rgResource.ID, "failWhen", "", fmt.Errorf("failed to parse failWhen expressions: %v", err))
+	}
+
+	// 10. Validate the ready timeout
+	var readyTimeout time.Duration
+	if rgResource.ReadyTimeout != nil {
+		readyTimeout = rgResource.ReadyTimeout.Duration
+		if readyTimeout <= 0 {
+			return nil, newValidationError(rgResource.ID, "readyTimeout", "", fmt.Errorf("readyTimeout must be positive, got %s", readyTimeout))
+		}
 	}
 
 	_, isNamespaced := namespacedResources[gvk]
@@ -330,12 +446,44 @@ func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResou
 		emulatedObject:         emulatedResource,
 		originalObject:         &unstructured.Unstructured{Object: resourceObject},
 		variables:              resourceVariables,
+		dependsOn:              rgResource.DependsOn,
 		readyWhenExpressions:   readyWhen,
 		includeWhenExpressions: includeWhen,
+		readinessPolicy:        rgResource.ReadinessPolicy.DeepCopy(),
+		failWhenExpressions:    failWhen,
+		readyTimeout:           readyTimeout,
 		namespaced:             isNamespaced,
 	}, nil
 }
 
+// validateReadinessPolicy checks that the durations of a readiness policy are
+// positive, and that its maximum interval isn't shorter than its initial delay.
+func validateReadinessPolicy(policy *v1alpha1.ReadinessPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	durations := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"initialDelay", policy.InitialDelay},
+		{"maxInterval", policy.MaxInterval},
+		{"timeout", policy.Timeout},
+	}
+	for _, d := range durations {
+		if d.duration != nil && d.duration.Duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", d.name, d.duration.Duration)
+		}
+	}
+	if policy.InitialDelay != nil && policy.MaxInterval != nil && policy.MaxInterval.Duration < policy.InitialDelay.Duration {
+		return fmt.Errorf("maxInterval %s is shorter than initialDelay %s", policy.MaxInterval.Duration, policy.InitialDelay.Duration)
+	}
+	if policy.BackoffFactor != nil && *policy.BackoffFactor < 1 {
+		return fmt.Errorf("backoffFactor must be at least 1, got %d", *policy.BackoffFactor)
+	}
+	return nil
+}
+
 // buildDependencyGraph builds the dependency graph between the resources in the
 // resource group. The dependency graph is an directed acyclic graph that represents
 // the relationships between the resources in the resource group. The graph is used
@@ -403,6 +551,46 @@ func (b *Builder) buildDependencyGraph(
 				}
 			}
 		}
+
+		// Explicit dependencies are used to order resources that don't refer to
+		// each other, e.g a namespace and the resources living in it.
+		for _, dependency := range resource.dependsOn {
+			if _, ok := resources[dependency]; !ok {
+				return nil, newValidationError(resourceName, "dependsOn", "", fmt.Errorf("resource %s depends on unknown resource %s", resourceName, dependency))
+			}
+			if dependency == resourceName {
+				return nil, newValidationError(resourceName, "dependsOn", "", fmt.Errorf("resource %s cannot depend on itself", resourceName))
+			}
+			resource.addDependency(dependency)
+			if err := directedAcyclicGraph.AddEdge(resourceName, dependency); err != nil {
+				return nil, err
+			}
+		}
+
+		// readyWhen, failWhen and includeWhen expressions can refer to other
+		// resources, which means they can only be evaluated once those
+		// resources are resolved. A readyWhen or failWhen expression referring
+		// to the resource itself doesn't introduce a dependency.
+		conditionExpressions := slices.Concat(
+			resource.readyWhenExpressions,
+			resource.failWhenExpressions,
+			resource.includeWhenExpressions,
+		)
+		for _, expression := range conditionExpressions {
+			resourceDependencies, _, err := extractDependencies(env, expression, resourceNames)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract dependencies: %w", err)
+			}
+			for _, dependency := range resourceDependencies {
+				if dependency == resourceName {
+					continue
+				}
+				resource.addDependency(dependency)
+				if err := directedAcyclicGraph.AddEdge(resourceName, dependency); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 
 	return directedAcyclicGraph, nil
@@ -415,9 +603,11 @@ func (b *Builder) buildDependencyGraph(
 // Since instances are defined using the "SimpleSchema" format, we use a different
 // approach to build the instance resource. We need to:
 func (b *Builder) buildInstanceResource(
+	ctx context.Context,
 	apiVersion, kind string,
 	rgDefinition *v1alpha1.Schema,
 	resources map[string]*Resource,
+	skipped []string,
 ) (*Resource, error) {
 	// The instance resource is the resource users will create in their cluster,
 	// to request the creation of the resources defined in the resource group.
@@ -444,7 +634,7 @@ func (b *Builder) buildInstanceResource(
 		return nil, fmt.Errorf("failed to build OpenAPI schema for instance: %w", err)
 	}
 
-	instanceStatusSchema, statusVariables, err := buildStatusSchema(rgDefinition, resources)
+	instanceStatusSchema, statusVariables, err := buildStatusSchema(ctx, rgDefinition, resources, skipped, b.celLimits)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build OpenAPI schema for instance status: %w", err)
 	}
@@ -526,10 +716,14 @@ func buildInstanceSpecSchema(rgSchema *v1alpha1.Schema) (*extv1.JSONSchemaProps,
 }
 
 // buildStatusSchema builds the status schema for the instance resource. The
-// status schema is inferred from the CEL expressions in the status field.
+// status schema is inferred from the CEL expressions in the status field. The
+// fields referring to skipped resources are left out.
 func buildStatusSchema(
+	ctx context.Context,
 	rgSchema *v1alpha1.Schema,
 	resources map[string]*Resource,
+	skipped []string,
+	celLimits krocel.Limits,
 ) (
 	*extv1.JSONSchemaProps,
 	[]variable.FieldDescriptor,
@@ -559,7 +753,15 @@ func buildStatusSchema(
 
 	// statusStructureParts := make([]schema.FieldDescriptor, 0, len(extracted))
 	statusDryRunResults := make(map[string][]ref.Val, len(fieldDescriptors))
+	statusFields := make([]variable.FieldDescriptor, 0, len(fieldDescriptors))
 	for _, found := range fieldDescriptors {
+		if slices.ContainsFunc(found.Expressions, func(expr string) bool {
+			return refersToSkippedResources(env, expr, skipped)
+		}) {
+			continue
+		}
+		statusFields = append(statusFields, found)
+
 		// For each expression in the extracted ExpressionField we need to dry-run
 		// the expression to infer the type of the status field.
 		evals := []ref.Val{}
@@ -572,7 +774,7 @@ func buildStatusSchema(
 			}
 
 			// resources is the context here.
-			value, err := dryRunExpression(env, expr, resources)
+			value, err := dryRunExpression(ctx, env, celLimits, expr, resources)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to dry-run expression: %w", err)
 			}
@@ -586,7 +788,7 @@ func buildStatusSchema(
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build JSON schema from status structure: %w", err)
 	}
-	return statusSchema, fieldDescriptors, nil
+	return statusSchema, statusFields, nil
 }
 
 // validateCELExpressionContext validates the given CEL expression in the context
@@ -613,24 +815,31 @@ func validateCELExpressionContext(env *cel.Env, expression string, resources []s
 // of emulated resources. We could've called this function evaluateExpression
 // but we chose to call it dryRunExpression to indicate that we are not actually
 // used for anything other than validating the expression and inspecting it
-func dryRunExpression(env *cel.Env, expression string, resources map[string]*Resource) (ref.Val, error) {
+//
+// Expressions whose estimated cost is above the CEL limits are rejected before
+// being evaluated.
+func dryRunExpression(ctx context.Context, env *cel.Env, celLimits krocel.Limits, expression string, resources map[string]*Resource) (ref.Val, error) {
 	ast, issues := env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("failed to compile expression: %w", issues.Err())
 	}
 
+	if err := celLimits.CheckEstimatedCost(env, ast); err != nil {
+		return nil, err
+	}
+
 	// TODO(a-hilaly): thinking about a creating a library to hide this...
-	program, err := env.Program(ast)
+	program, err := env.Program(ast, celLimits.ProgramOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create program: %w", err)
 	}
 
-	context := map[string]interface{}{}
+	vars := map[string]interface{}{}
 	for resourceName, resource := range resources {
-		context[resourceName] = resource.emulatedObject.Object
+		vars[resourceName] = resource.emulatedObject.Object
 	}
 
-	output, _, err := program.Eval(context)
+	output, err := celLimits.Eval(ctx, program, vars)
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate expression: %w", err)
 	}
@@ -668,6 +877,19 @@ func extractDependencies(env *cel.Env, expression string, resourceNames []string
 	return dependencies, isStatic, nil
 }
 
+// refersToSkippedResources returns true if the given CEL expression refers to
+// one of the skipped resources, the resources that failed to build.
+func refersToSkippedResources(env *cel.Env, expression string, skipped []string) bool {
+	if len(skipped) == 0 {
+		return false
+	}
+	inspectionResult, err := ast.NewInspectorWithEnv(env, skipped, nil).Inspect(expression)
+	if err != nil {
+		return false
+	}
+	return len(inspectionResult.ResourceDependencies) > 0
+}
+
 // validateResourceCELExpressions tries to validate the CEL expressions in the
 // resources against the resources defined in the resource group.
 //
@@ -676,11 +898,15 @@ func extractDependencies(env *cel.Env, expression string, resourceNames []string
 // we evalute A's CEL expressions against 2 emulated resources B and C. Then
 // we evaluate B's CEL expressions against 2 emulated resources A and C, and so
 // on.
-func validateResourceCELExpressions(resources map[string]*Resource, instance *Resource) error {
+//
+// All the expressions are validated, the returned ValidationErrors contains an
+// entry per invalid expression. Resources are visited in alphabetical order, to
+// keep the order of the errors stable. The expressions referring to skipped
+// resources aren't validated.
+func validateResourceCELExpressions(ctx context.Context, resources map[string]*Resource, skipped []string, instance *Resource, celLimits krocel.Limits) error {
 	resourceNames := maps.Keys(resources)
 	// We also want to allow users to refer to the instance spec in their expressions.
 	resourceNames = append(resourceNames, "schema")
-	conditionFieldNames := []string{"schema"}
 
 	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
 	if err != nil {
@@ -692,106 +918,120 @@ func validateResourceCELExpressions(resources map[string]*Resource, instance *Re
 		delete(instanceEmulatedCopy.Object, "kind")
 		delete(instanceEmulatedCopy.Object, "status")
 	}
+	schemaResource := &Resource{
+		emulatedObject: &unstructured.Unstructured{
+			Object: instanceEmulatedCopy.Object,
+		},
+	}
 
-	for _, resource := range resources {
-		for _, resourceVariable := range resource.variables {
-			for _, expression := range resourceVariable.Expressions {
-				err := validateCELExpressionContext(env, expression, resourceNames)
-				if err != nil {
-					return fmt.Errorf("failed to validate expression context: '%s' %w", expression, err)
-				}
-
-				// create context
-				context := map[string]*Resource{}
-				for resourceName, contextResource := range resources {
-					// exclude the resource we are validating
-					if resourceName != resource.id {
-						context[resourceName] = contextResource
-					}
-				}
-				// add instance spec to the context
-				context["schema"] = &Resource{
-					emulatedObject: &unstructured.Unstructured{
-						Object: instanceEmulatedCopy.Object,
-					},
-				}
+	// validateExpression validates the context of an expression, dry-runs it
+	// against the given resources and returns its output.
+	validateExpression := func(expression string, context map[string]*Resource) (ref.Val, error) {
+		if err := validateCELExpressionContext(env, expression, resourceNames); err != nil {
+			return nil, fmt.Errorf("failed to validate expression context: %w", err)
+		}
+		output, err := dryRunExpression(ctx, env, celLimits, expression, context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dry-run expression: %w", err)
+		}
+		return output, nil
+	}
 
-				_, err = dryRunExpression(env, expression, context)
-				if err != nil {
-					return fmt.Errorf("failed to dry-run expression %s: %w", expression, err)
-				}
+	var errs ValidationErrors
+	ids := maps.Keys(resources)
+	slices.Sort(ids)
+	for _, id := range ids {
+		resource := resources[id]
+
+		// Template expressions and includeWhen expressions are evaluated
+		// against the other resources, the resource itself doesn't exist yet.
+		// readyWhen expressions can also refer to the resource itself.
+		othersContext := map[string]*Resource{"schema": schemaResource}
+		allContext := map[string]*Resource{"schema": schemaResource}
+		for resourceName, contextResource := range resources {
+			if resourceName != resource.id {
+				othersContext[resourceName] = contextResource
 			}
+			allContext[resourceName] = contextResource
+		}
 
-			// validate readyWhen Expressions for resource
-			// Only accepting expressions accessing the status and spec for now
-			// and need to evaluate to a boolean type
-			//
-			// TODO(michaelhtm) It shares some of the logic with the loop from above..maybe
-			// we can refactor them or put it in one function.
-			// I would also suggest separating the dryRuns of readyWhenExpressions
-			// and the resourceExpressions.
-			for _, readyWhenExpression := range resource.readyWhenExpressions {
-				fieldEnv, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{resource.id}))
-				if err != nil {
-					return fmt.Errorf("failed to create CEL environment: %w", err)
-				}
-
-				err = validateCELExpressionContext(fieldEnv, readyWhenExpression, []string{resource.id})
-				if err != nil {
-					return fmt.Errorf("failed to validate expression context: '%s' %w", readyWhenExpression, err)
-				}
-				// create context
-				// add resource fields to the context
-				resourceEmulatedCopy := resource.emulatedObject.DeepCopy()
-				if resourceEmulatedCopy != nil && resourceEmulatedCopy.Object != nil {
-					delete(resourceEmulatedCopy.Object, "apiVersion")
-					delete(resourceEmulatedCopy.Object, "kind")
+		for _, resourceVariable := range resource.variables {
+			for _, expression := range resourceVariable.Expressions {
+				if refersToSkippedResources(env, expression, skipped) {
+					continue
 				}
-				context := map[string]*Resource{}
-				context[resource.id] = &Resource{
-					emulatedObject: resourceEmulatedCopy,
+				if _, err := validateExpression(expression, othersContext); err != nil {
+					errs = append(errs, newValidationError(resource.id, resourceVariable.Path, expression, err))
 				}
-				output, err := dryRunExpression(fieldEnv, readyWhenExpression, context)
+			}
+		}
 
-				if err != nil {
-					return fmt.Errorf("failed to dry-run expression %s: %w", readyWhenExpression, err)
-				}
-				if !krocel.IsBoolType(output) {
-					return fmt.Errorf("output of readyWhen expression %s can only be of type bool", readyWhenExpression)
-				}
+		// readyWhen expressions can refer to the instance spec and to any
+		// resource in the graph, including the resource itself. They need to
+		// evaluate to a boolean type.
+		for i, readyWhenExpression := range resource.readyWhenExpressions {
+			path := fmt.Sprintf("readyWhen[%d]", i)
+			if refersToSkippedResources(env, readyWhenExpression, skipped) {
+				continue
+			}
+			output, err := validateExpression(readyWhenExpression, allContext)
+			if err != nil {
+				errs = append(errs, newValidationError(resource.id, path, readyWhenExpression, err))
+				continue
+			}
+			if !krocel.IsBoolType(output) {
+				errs = append(errs, newValidationError(resource.id, path, readyWhenExpression,
+					fmt.Errorf("output of readyWhen expression %s can only be of type bool", readyWhenExpression)))
 			}
+		}
 
-			for _, includeWhenExpression := range resource.includeWhenExpressions {
-				instanceEnv, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
-				if err != nil {
-					return fmt.Errorf("failed to create CEL environment: %w", err)
-				}
+		// failWhen expressions are validated the same way as the readyWhen
+		// expressions.
+		for i, failWhenExpression := range resource.failWhenExpressions {
+			path := fmt.Sprintf("failWhen[%d]", i)
+			if refersToSkippedResources(env, failWhenExpression, skipped) {
+				continue
+			}
+			output, err := validateExpression(failWhenExpression, allContext)
+			if err != nil {
+				errs = append(errs, newValidationError(resource.id, path, failWhenExpression, err))
+				continue
+			}
+			if !krocel.IsBoolType(output) {
+				errs = append(errs, newValidationError(resource.id, path, failWhenExpression,
+					fmt.Errorf("output of failWhen expression %s can only be of type bool", failWhenExpression)))
+			}
+		}
 
-				err = validateCELExpressionContext(instanceEnv, includeWhenExpression, conditionFieldNames)
-				if err != nil {
-					return fmt.Errorf("failed to validate expression context: '%s' %w", includeWhenExpression, err)
-				}
-				// create context
-				context := map[string]*Resource{}
-				// for now we will only support the instance context for condition expressions.
-				// With this decision we will decide in creation time, and update time
-				// If we'll be creating resources or not
-				context["schema"] = &Resource{
-					emulatedObject: &unstructured.Unstructured{
-						Object: instanceEmulatedCopy.Object,
-					},
-				}
+		// includeWhen expressions can refer to the instance spec and to any
+		// other resource in the graph. A resource cannot refer to itself, since
+		// the expression decides whether the resource is created in the first
+		// place.
+		for i, includeWhenExpression := range resource.includeWhenExpressions {
+			path := fmt.Sprintf("includeWhen[%d]", i)
+			if refersToSkippedResources(env, includeWhenExpression, skipped) {
+				continue
+			}
+			dependencies, _, err := extractDependencies(env, includeWhenExpression, resourceNames)
+			if err == nil && slices.Contains(dependencies, resource.id) {
+				err = fmt.Errorf("includeWhen expression %s cannot refer to the resource itself", includeWhenExpression)
+			}
+			if err != nil {
+				errs = append(errs, newValidationError(resource.id, path, includeWhenExpression, err))
+				continue
+			}
 
-				output, err := dryRunExpression(instanceEnv, includeWhenExpression, context)
-				if err != nil {
-					return fmt.Errorf("failed to dry-run expression %s: %w", includeWhenExpression, err)
-				}
-				if !krocel.IsBoolType(output) {
-					return fmt.Errorf("output of condition expression %s can only be of type bool", includeWhenExpression)
-				}
+			output, err := validateExpression(includeWhenExpression, othersContext)
+			if err != nil {
+				errs = append(errs, newValidationError(resource.id, path, includeWhenExpression, err))
+				continue
+			}
+			if !krocel.IsBoolType(output) {
+				errs = append(errs, newValidationError(resource.id, path, includeWhenExpression,
+					fmt.Errorf("output of condition expression %s can only be of type bool", includeWhenExpression)))
 			}
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
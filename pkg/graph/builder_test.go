@@ -14,16 +14,23 @@
 package graph
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
+	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	krocel "github.com/awslabs/kro/pkg/cel"
 	"github.com/awslabs/kro/pkg/graph/emulator"
 	"github.com/awslabs/kro/pkg/graph/variable"
 	"github.com/awslabs/kro/pkg/testutil/generator"
 	"github.com/awslabs/kro/pkg/testutil/k8s"
+	tracingtest "github.com/awslabs/kro/pkg/testutil/tracing"
 )
 
 func TestGraphBuilder_Validation(t *testing.T) {
@@ -185,7 +192,7 @@ func TestGraphBuilder_Validation(t *testing.T) {
 				}, nil, nil),
 			},
 			wantErr: true,
-			errMsg:  "failed to validate resource CEL expression",
+			errMsg:  "failed to dry-run expression",
 		},
 		{
 			name: "valid VPC with valid conditional subnets",
@@ -382,7 +389,7 @@ func TestGraphBuilder_Validation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rg := generator.NewResourceGroup("test-group", tt.resourceGroupOpts...)
-			_, err := builder.NewResourceGroup(rg)
+			_, err := builder.NewResourceGroup(context.Background(), rg)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -509,7 +516,12 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 				assert.Contains(t, clusterDeps, "subnet2")
 
 				// Validate topological order
-				assert.Equal(t, []string{"clusterpolicy", "clusterrole", "vpc", "subnet1", "subnet2", "cluster"}, g.TopologicalOrder)
+				assert.Equal(t, []string{"clusterpolicy", "vpc", "clusterrole", "subnet1", "subnet2", "cluster"}, g.TopologicalOrder)
+				assert.Equal(t, [][]string{
+					{"clusterpolicy", "vpc"},
+					{"clusterrole", "subnet1", "subnet2"},
+					{"cluster"},
+				}, g.TopologicalLevels)
 			},
 		},
 		{
@@ -571,7 +583,7 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 				}, nil, nil),
 			},
 			wantErr: true,
-			errMsg:  "This would create a cycle",
+			errMsg:  "graph has a cycle: role1 -> role2 -> role1",
 		},
 		{
 			name: "independent pods",
@@ -726,7 +738,7 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 				}, nil, nil),
 			},
 			wantErr: true,
-			errMsg:  "This would create a cycle",
+			errMsg:  "graph has a cycle: pod1 -> pod4 -> pod3 -> pod2 -> pod1",
 		},
 		{
 			name: "shared infrastructure dependencies",
@@ -920,8 +932,9 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 				// Validate topological order
 				assert.Equal(t, []string{
 					"policy",
-					"role",
 					"vpc",
+					"role",
+					"secgroup",
 					"subnet1",
 					"subnet2",
 					"subnet3",
@@ -929,8 +942,13 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					"cluster2",
 					"cluster3",
 					"monitor",
-					"secgroup",
 				}, g.TopologicalOrder)
+				assert.Equal(t, [][]string{
+					{"policy", "vpc"},
+					{"role", "secgroup", "subnet1", "subnet2", "subnet3"},
+					{"cluster1", "cluster2", "cluster3"},
+					{"monitor"},
+				}, g.TopologicalLevels)
 			},
 		},
 	}
@@ -938,7 +956,7 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rg := generator.NewResourceGroup("testgroup", tt.resourceGroupOpts...)
-			g, err := builder.NewResourceGroup(rg)
+			g, err := builder.NewResourceGroup(context.Background(), rg)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -1188,7 +1206,7 @@ func TestGraphBuilder_ExpressionParsing(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rg := generator.NewResourceGroup("testgroup", tt.resourceGroupOpts...)
-			g, err := builder.NewResourceGroup(rg)
+			g, err := builder.NewResourceGroup(context.Background(), rg)
 			require.NoError(t, err)
 			if tt.validateVars != nil {
 				tt.validateVars(t, g)
@@ -1221,8 +1239,749 @@ func validateVariables(t *testing.T, actual []*variable.ResourceField, expected
 	assert.ElementsMatch(t, expected, actualVars)
 }
 
+func TestGraphBuilder_ConditionDependencies(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name         string
+		readyWhen    []string
+		includeWhen  []string
+		wantErr      bool
+		errMsg       string
+		validateDeps func(*testing.T, *Graph)
+	}{
+		{
+			name:      "readyWhen referencing schema and another resource",
+			readyWhen: []string{"${subnet.status.state == vpc.status.state && schema.spec.name != ''}"},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet"].GetDependencies())
+				assert.Equal(t, []string{"vpc", "subnet"}, g.TopologicalOrder)
+			},
+		},
+		{
+			name:      "readyWhen referencing only the resource itself",
+			readyWhen: []string{"${subnet.status.state == 'available'}"},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Empty(t, g.Resources["subnet"].GetDependencies())
+			},
+		},
+		{
+			name:        "includeWhen referencing another resource",
+			includeWhen: []string{"${vpc.status.state == 'available'}"},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet"].GetDependencies())
+				assert.Equal(t, []string{"vpc", "subnet"}, g.TopologicalOrder)
+			},
+		},
+		{
+			name:        "includeWhen referencing the resource itself",
+			includeWhen: []string{"${subnet.status.state == 'available'}"},
+			wantErr:     true,
+			errMsg:      "cannot refer to the resource itself",
+		},
+		{
+			name:        "includeWhen referencing an unknown resource",
+			includeWhen: []string{"${cluster.status.status == 'ACTIVE'}"},
+			wantErr:     true,
+			errMsg:      "cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rg := generator.NewResourceGroup("test-group",
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.1.0/24",
+					},
+				}, tt.readyWhen, tt.includeWhen),
+			)
+
+			g, err := builder.NewResourceGroup(context.Background(), rg)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.validateDeps != nil {
+				tt.validateDeps(t, g)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_DependsOn(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name         string
+		dependsOn    map[string][]string
+		wantErr      bool
+		errMsg       string
+		validateDeps func(*testing.T, *Graph)
+	}{
+		{
+			name: "explicit dependency without data reference",
+			dependsOn: map[string][]string{
+				"vpc": {"policy"},
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"policy"}, g.Resources["vpc"].GetDependencies())
+				assert.ElementsMatch(t, []string{"vpc"}, g.Resources["subnet"].GetDependencies())
+				assert.Equal(t, []string{"policy", "vpc", "subnet"}, g.TopologicalOrder)
+			},
+		},
+		{
+			name: "explicit dependency duplicating a data reference",
+			dependsOn: map[string][]string{
+				"subnet": {"vpc"},
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet"].GetDependencies())
+			},
+		},
+		{
+			name: "unknown resource",
+			dependsOn: map[string][]string{
+				"vpc": {"namespace"},
+			},
+			wantErr: true,
+			errMsg:  "resource vpc depends on unknown resource namespace",
+		},
+		{
+			name: "self dependency",
+			dependsOn: map[string][]string{
+				"vpc": {"vpc"},
+			},
+			wantErr: true,
+			errMsg:  "resource vpc cannot depend on itself",
+		},
+		{
+			name: "cycle with a data reference",
+			dependsOn: map[string][]string{
+				"vpc": {"subnet"},
+			},
+			wantErr: true,
+			errMsg:  "cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []generator.ResourceGroupOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("policy", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Policy",
+					"metadata": map[string]interface{}{
+						"name": "policy",
+					},
+					"spec": map[string]interface{}{
+						"name":     "policy",
+						"document": "{}",
+					},
+				}, nil, nil),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.1.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+			}
+			for id, dependsOn := range tt.dependsOn {
+				opts = append(opts, generator.WithResourceFields(id, func(r *krov1alpha1.Resource) {
+					r.DependsOn = dependsOn
+				}))
+			}
+
+			g, err := builder.NewResourceGroup(context.Background(), generator.NewResourceGroup("test-group", opts...))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.validateDeps != nil {
+				tt.validateDeps(t, g)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_ReadinessPolicy(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+	factor := func(f int32) *int32 {
+		return &f
+	}
+
+	tests := []struct {
+		name    string
+		policy  *krov1alpha1.ReadinessPolicy
+		wantErr string
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name: "valid policy",
+			policy: &krov1alpha1.ReadinessPolicy{
+				InitialDelay:  duration(time.Second),
+				MaxInterval:   duration(time.Minute),
+				BackoffFactor: factor(2),
+				Timeout:       duration(10 * time.Minute),
+			},
+		},
+		{
+			name: "negative initial delay",
+			policy: &krov1alpha1.ReadinessPolicy{
+				InitialDelay: duration(-time.Second),
+			},
+			wantErr: "initialDelay must be positive",
+		},
+		{
+			name: "zero timeout",
+			policy: &krov1alpha1.ReadinessPolicy{
+				Timeout: duration(0),
+			},
+			wantErr: "timeout must be positive",
+		},
+		{
+			name: "max interval shorter than initial delay",
+			policy: &krov1alpha1.ReadinessPolicy{
+				InitialDelay: duration(time.Minute),
+				MaxInterval:  duration(time.Second),
+			},
+			wantErr: "maxInterval 1s is shorter than initialDelay 1m0s",
+		},
+		{
+			name: "backoff factor below one",
+			policy: &krov1alpha1.ReadinessPolicy{
+				BackoffFactor: factor(0),
+			},
+			wantErr: "backoffFactor must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rg := generator.NewResourceGroup("test-group",
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+					},
+				}, nil, nil),
+				generator.WithResourceFields("vpc", func(r *krov1alpha1.Resource) {
+					r.ReadinessPolicy = tt.policy
+				}),
+			)
+
+			g, err := builder.NewResourceGroup(context.Background(), rg)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				var errs ValidationErrors
+				require.ErrorAs(t, err, &errs)
+				require.Len(t, errs, 1)
+				assert.Equal(t, "vpc", errs[0].ResourceID)
+				assert.Equal(t, "readinessPolicy", errs[0].Path)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.policy, g.Resources["vpc"].GetReadinessPolicy())
+		})
+	}
+}
+
+func TestGraphBuilder_FailWhen(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name         string
+		failWhen     []string
+		readyTimeout *metav1.Duration
+		wantErr      *ValidationError
+		validate     func(*testing.T, *Graph)
+	}{
+		{
+			name:         "expressions referring to the resource and to a dependency",
+			failWhen:     []string{`${subnet.status.state == "failed"}`, `${vpc.status.state == "deleted"}`},
+			readyTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			validate: func(t *testing.T, g *Graph) {
+				subnet := g.Resources["subnet"]
+				assert.Equal(t, []string{`subnet.status.state == "failed"`, `vpc.status.state == "deleted"`}, subnet.GetFailWhenExpressions())
+				assert.Equal(t, 10*time.Minute, subnet.GetReadyTimeout())
+				assert.Equal(t, []string{"vpc"}, subnet.GetDependencies())
+			},
+		},
+		{
+			name:     "expression referring to a resource that doesn't depend on the subnet",
+			failWhen: []string{`${policy.metadata.name == "deleted"}`},
+			validate: func(t *testing.T, g *Graph) {
+				assert.ElementsMatch(t, []string{"vpc", "policy"}, g.Resources["subnet"].GetDependencies())
+				assert.Zero(t, g.Resources["subnet"].GetReadyTimeout())
+			},
+		},
+		{
+			name:     "non boolean expression",
+			failWhen: []string{"${subnet.status.state}"},
+			wantErr: &ValidationError{
+				ResourceID: "subnet",
+				Path:       "failWhen[0]",
+				Expression: "subnet.status.state",
+			},
+		},
+		{
+			name:     "unknown field",
+			failWhen: []string{`${subnet.status.unknown == "failed"}`},
+			wantErr: &ValidationError{
+				ResourceID: "subnet",
+				Path:       "failWhen[0]",
+				Expression: `subnet.status.unknown == "failed"`,
+			},
+		},
+		{
+			name:         "negative ready timeout",
+			readyTimeout: &metav1.Duration{Duration: -time.Minute},
+			wantErr: &ValidationError{
+				ResourceID: "subnet",
+				Path:       "readyTimeout",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rg := generator.NewResourceGroup("test-group",
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("policy", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Policy",
+					"metadata": map[string]interface{}{
+						"name": "policy",
+					},
+					"spec": map[string]interface{}{
+						"name":     "policy",
+						"document": "{}",
+					},
+				}, nil, nil),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.1.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+				generator.WithResourceFields("subnet", func(r *krov1alpha1.Resource) {
+					r.FailWhen = tt.failWhen
+					r.ReadyTimeout = tt.readyTimeout
+				}),
+			)
+
+			g, err := builder.NewResourceGroup(context.Background(), rg)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				var errs ValidationErrors
+				require.ErrorAs(t, err, &errs)
+				require.Len(t, errs, 1, err.Error())
+				assert.Equal(t, tt.wantErr.ResourceID, errs[0].ResourceID)
+				assert.Equal(t, tt.wantErr.Path, errs[0].Path)
+				assert.Equal(t, tt.wantErr.Expression, errs[0].Expression)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, g)
+		})
+	}
+}
+
+func TestGraphBuilder_ValidationErrors(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name              string
+		resourceGroupOpts []generator.ResourceGroupOption
+		wantErrs          []ValidationError
+	}{
+		{
+			name: "multiple naming convention violations",
+			resourceGroupOpts: []generator.ResourceGroupOption{
+				generator.WithSchema(
+					"test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("Vpc", map[string]interface{}{}, nil, nil),
+				generator.WithResource("spec", map[string]interface{}{}, nil, nil),
+			},
+			wantErrs: []ValidationError{
+				{Path: "spec.schema.kind"},
+				{ResourceID: "Vpc", Path: "id"},
+				{ResourceID: "spec", Path: "id"},
+			},
+		},
+		{
+			name: "multiple invalid resources",
+			resourceGroupOpts: []generator.ResourceGroupOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"kind": "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+				}, []string{"invalid ! syntax"}, nil),
+			},
+			wantErrs: []ValidationError{
+				{ResourceID: "vpc", Path: "template"},
+				{ResourceID: "subnet", Path: "readyWhen"},
+			},
+		},
+		{
+			name: "multiple invalid expressions",
+			resourceGroupOpts: []generator.ResourceGroupOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"${schema.spec.nonexistent}"},
+					},
+				}, nil, []string{"${schema.spec.name}"}),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.1.0/24",
+						"vpcID":     "${vpc.status.nonexistentField}",
+					},
+				}, []string{"${subnet.status.state}"}, nil),
+			},
+			wantErrs: []ValidationError{
+				{ResourceID: "subnet", Path: "spec.vpcID", Expression: "vpc.status.nonexistentField"},
+				{ResourceID: "subnet", Path: "readyWhen[0]", Expression: "subnet.status.state"},
+				{ResourceID: "vpc", Path: "spec.cidrBlocks[0]", Expression: "schema.spec.nonexistent"},
+				{ResourceID: "vpc", Path: "includeWhen[0]", Expression: "schema.spec.name"},
+			},
+		},
+		{
+			name: "errors of every stage",
+			resourceGroupOpts: []generator.ResourceGroupOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				// Invalid resource ID
+				generator.WithResource("Vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+				}, nil, nil),
+				// Invalid template
+				generator.WithResource("subnet", map[string]interface{}{
+					"kind": "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+				}, nil, nil),
+				// Expressions referring to the failed resources aren't
+				// validated, the others are
+				generator.WithResource("publicSubnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.nonexistent}",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "${subnet.spec.cidrBlock}",
+						"vpcID":     "${Vpc.status.vpcID}",
+					},
+				}, nil, nil),
+			},
+			wantErrs: []ValidationError{
+				{ResourceID: "Vpc", Path: "id"},
+				{ResourceID: "subnet", Path: "template"},
+				{ResourceID: "publicSubnet", Path: "metadata.name", Expression: "schema.spec.nonexistent"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rg := generator.NewResourceGroup("test-group", tt.resourceGroupOpts...)
+			_, err := builder.NewResourceGroup(context.Background(), rg)
+			require.Error(t, err)
+
+			var errs ValidationErrors
+			require.ErrorAs(t, err, &errs)
+			require.Len(t, errs, len(tt.wantErrs), err.Error())
+			for i, want := range tt.wantErrs {
+				assert.Equal(t, want.ResourceID, errs[i].ResourceID)
+				assert.Equal(t, want.Path, errs[i].Path)
+				assert.Equal(t, want.Expression, errs[i].Expression)
+				assert.NotEmpty(t, errs[i].Message)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_CELCostLimits(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+		celLimits:        krocel.DefaultLimits(),
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "expression within cost limit",
+			expression: "${vpc.spec.cidrBlocks.map(x, x.lowerAscii())[0]}",
+		},
+		{
+			name:       "nested comprehension exceeding cost limit",
+			expression: "${vpc.spec.cidrBlocks.filter(x, vpc.spec.cidrBlocks.exists(y, y == x))[0]}",
+			wantErr:    true,
+			errMsg:     "CEL cost limit exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rg := generator.NewResourceGroup("test-group",
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "testvpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": tt.expression,
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+			)
+
+			_, err := builder.NewResourceGroup(context.Background(), rg)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGraphBuilder_Tracing(t *testing.T) {
+	exporter := tracingtest.NewInMemoryExporter(t)
+
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+		celLimits:        krocel.DefaultLimits(),
+	}
+
+	rg := generator.NewResourceGroup("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name": "string",
+			},
+			nil,
+		),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlocks": []interface{}{"10.0.0.0/16"},
+			},
+		}, nil, nil),
+	)
+
+	_, err := builder.NewResourceGroup(context.Background(), rg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"graph.discovery",
+		"graph.schemaResolution",
+		"graph.emulation",
+		"graph.celDryRun",
+		"graph.dag",
+		"graph.NewResourceGroup",
+	}, tracingtest.SpanNames(exporter))
+
+	spans := exporter.GetSpans()
+	root := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID())
+		assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID())
+	}
+
+	// Every phase is timed as well.
+	assert.Equal(t, 6, promtestutil.CollectAndCount(buildDuration))
+}
+
 func TestNewBuilder(t *testing.T) {
-	builder, err := NewBuilder(&rest.Config{})
+	builder, err := NewBuilder(&rest.Config{}, krocel.DefaultLimits())
 	assert.Nil(t, err)
 	assert.NotNil(t, builder)
 }
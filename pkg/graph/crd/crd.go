@@ -34,7 +34,7 @@ func newCRD(apiVersion, kind string, schema *extv1.JSONSchemaProps) *extv1.Custo
 	return &extv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            fmt.Sprintf("%s.%s", pluralKind, v1alpha1.KroDomainName),
-			OwnerReferences: nil, // Injecting owner references is the responsibility of the caller.
+			OwnerReferences: nil, // Generated CRDs are traced to their resource group through labels and annotations.
 		},
 		Spec: extv1.CustomResourceDefinitionSpec{
 			Group: v1alpha1.KroDomainName,
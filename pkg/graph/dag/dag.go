@@ -53,20 +53,21 @@ func (d *DirectedAcyclicGraph) AddVertex(id string) error {
 	return nil
 }
 
+// CycleError is returned when a cycle is found in the graph.
 type CycleError struct {
-	From, to string
-	Cycle    []string
+	Cycle []string
 }
 
 func (e *CycleError) Error() string {
-	return fmt.Sprintf("Cannot add edge from %s to %s. This would create a cycle: %s", e.From, e.to, formatCycle(e.Cycle))
+	return fmt.Sprintf("graph has a cycle: %s", formatCycle(e.Cycle))
 }
 
 func formatCycle(cycle []string) string {
 	return strings.Join(cycle, " -> ")
 }
 
-// AddEdge adds a directed edge from one node to another.
+// AddEdge adds a directed edge from one node to another. Cycles aren't
+// checked here, TopologicalLevels reports them once the graph is complete.
 func (d *DirectedAcyclicGraph) AddEdge(from, to string) error {
 	fromNode, fromExists := d.Vertices[from]
 	_, toExists := d.Vertices[to]
@@ -81,60 +82,107 @@ func (d *DirectedAcyclicGraph) AddEdge(from, to string) error {
 	}
 
 	fromNode.Edges[to] = struct{}{}
-
-	// Check if the graph is still a DAG
-	hasCycle, cycle := d.HasCycle()
-	if hasCycle {
-		// Ehmmm, we have a cycle, let's remove the edge we just added
-		delete(fromNode.Edges, to)
-		return &CycleError{
-			From:  from,
-			to:    to,
-			Cycle: cycle,
-		}
-	}
-
 	return nil
 }
 
+// TopologicalSort returns the vertices of the graph in topological order,
+// dependencies first. The order is the concatenation of the topological levels.
 func (d *DirectedAcyclicGraph) TopologicalSort() ([]string, error) {
-	if cyclic, _ := d.HasCycle(); cyclic {
-		return nil, fmt.Errorf("graph has a cycle")
+	levels, err := d.TopologicalLevels()
+	if err != nil {
+		return nil, err
 	}
 
-	visited := make(map[string]bool)
-	var order []string
-
-	// Get a sorted list of all vertices
-	vertices := d.GetVertices()
+	order := make([]string, 0, len(d.Vertices))
+	for _, level := range levels {
+		order = append(order, level...)
+	}
+	return order, nil
+}
 
-	var dfs func(string)
-	dfs = func(node string) {
-		visited[node] = true
+// TopologicalLevels groups the vertices of the graph in levels using Kahn's
+// algorithm. The dependencies of a vertex all sit in earlier levels, meaning
+// that the vertices of a same level can be processed together. Vertices are
+// sorted alphabetically within a level, to ensure a deterministic result.
+//
+// If the graph has a cycle, a *CycleError containing the cycle is returned.
+func (d *DirectedAcyclicGraph) TopologicalLevels() ([][]string, error) {
+	// inDegree is the number of dependencies of a vertex that are not part
+	// of a level yet. Edges point from a vertex to its dependencies, so we
+	// also need the reverse edges to find the dependents of a vertex.
+	inDegree := make(map[string]int, len(d.Vertices))
+	dependents := make(map[string][]string, len(d.Vertices))
+	for id, vertex := range d.Vertices {
+		inDegree[id] = len(vertex.Edges)
+		for dependency := range vertex.Edges {
+			dependents[dependency] = append(dependents[dependency], id)
+		}
+	}
 
-		// Sort the neighbors to ensure deterministic order
-		neighbors := make([]string, 0, len(d.Vertices[node].Edges))
-		for neighbor := range d.Vertices[node].Edges {
-			neighbors = append(neighbors, neighbor)
+	var level []string
+	for _, id := range d.GetVertices() {
+		if inDegree[id] == 0 {
+			level = append(level, id)
 		}
-		sort.Strings(neighbors)
+	}
 
-		for _, neighbor := range neighbors {
-			if !visited[neighbor] {
-				dfs(neighbor)
+	var levels [][]string
+	sorted := 0
+	for len(level) > 0 {
+		levels = append(levels, level)
+		sorted += len(level)
+
+		var next []string
+		for _, id := range level {
+			for _, dependent := range dependents[id] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
 			}
 		}
-		order = append(order, node)
+		sort.Strings(next)
+		level = next
 	}
 
-	// Visit nodes in a deterministic order
-	for _, node := range vertices {
-		if !visited[node] {
-			dfs(node)
+	if sorted != len(d.Vertices) {
+		return nil, &CycleError{Cycle: d.findCycle(inDegree)}
+	}
+	return levels, nil
+}
+
+// findCycle returns a cycle among the vertices that couldn't be sorted by
+// TopologicalLevels. Each of them has at least one dependency that couldn't
+// be sorted either, so following those dependencies always leads back to a
+// vertex we've already seen.
+func (d *DirectedAcyclicGraph) findCycle(inDegree map[string]int) []string {
+	var node string
+	for _, id := range d.GetVertices() {
+		if inDegree[id] > 0 {
+			node = id
+			break
 		}
 	}
 
-	return order, nil
+	position := make(map[string]int)
+	var path []string
+	for {
+		if i, seen := position[node]; seen {
+			return append(path[i:], node)
+		}
+		position[node] = len(path)
+		path = append(path, node)
+
+		// Follow the smallest unsorted dependency, to ensure a deterministic
+		// result.
+		next := ""
+		for dependency := range d.Vertices[node].Edges {
+			if inDegree[dependency] > 0 && (next == "" || dependency < next) {
+				next = dependency
+			}
+		}
+		node = next
+	}
 }
 
 // GetVertices returns the nodes in the graph in sorted alphabetical
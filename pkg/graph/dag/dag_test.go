@@ -65,13 +65,9 @@ func TestDAGHasCycle(t *testing.T) {
 		t.Error("DAG incorrectly reported a cycle")
 	}
 
-	if err := d.AddEdge("C", "A"); err == nil {
-		t.Error("Expected error when creating a cycle, but got nil")
+	if err := d.AddEdge("C", "A"); err != nil {
+		t.Errorf("Unexpected error when creating a cycle: %v", err)
 	}
-
-	// pointless to test for the cycle here, so we need to emulate one
-	// by artificially adding a cycle.
-	d.Vertices["C"].Edges["A"] = struct{}{}
 	if cyclic, _ := d.HasCycle(); !cyclic {
 		t.Error("DAG failed to detect cycle")
 	}
@@ -106,6 +102,74 @@ func TestDAGTopologicalSort(t *testing.T) {
 	}
 }
 
+func TestDAGTopologicalLevels(t *testing.T) {
+	d := NewDirectedAcyclicGraph()
+	d.AddVertex("A")
+	d.AddVertex("B")
+	d.AddVertex("C")
+	d.AddVertex("D")
+	d.AddVertex("E")
+	d.AddVertex("F")
+
+	d.AddEdge("A", "B")
+	d.AddEdge("A", "C")
+	d.AddEdge("B", "D")
+	d.AddEdge("C", "D")
+	d.AddEdge("E", "A")
+	d.AddEdge("E", "F")
+
+	levels, err := d.TopologicalLevels()
+	if err != nil {
+		t.Fatalf("topological levels failed: %v", err)
+	}
+
+	expected := [][]string{{"D", "F"}, {"B", "C"}, {"A"}, {"E"}}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("TopologicalLevels() = %v, want %v", levels, expected)
+	}
+
+	order, err := d.TopologicalSort()
+	if err != nil {
+		t.Fatalf("topological sort failed: %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"D", "F", "B", "C", "A", "E"}) {
+		t.Errorf("TopologicalSort() = %v, want the concatenation of %v", order, expected)
+	}
+}
+
+func TestDAGTopologicalLevelsCycle(t *testing.T) {
+	d := NewDirectedAcyclicGraph()
+	d.AddVertex("A")
+	d.AddVertex("B")
+	d.AddVertex("C")
+	d.AddVertex("D")
+
+	d.AddEdge("A", "B")
+	d.AddEdge("B", "C")
+	d.AddEdge("D", "A")
+	d.AddEdge("C", "A")
+
+	_, err := d.TopologicalLevels()
+	if err == nil {
+		t.Fatal("Expected error when sorting a graph with a cycle, but got nil")
+	}
+	cycleErr, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("Expected a *CycleError, got %T", err)
+	}
+	expected := []string{"A", "B", "C", "A"}
+	if !reflect.DeepEqual(cycleErr.Cycle, expected) {
+		t.Errorf("Cycle = %v, want %v", cycleErr.Cycle, expected)
+	}
+	if err.Error() != "graph has a cycle: A -> B -> C -> A" {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	if _, err := d.TopologicalSort(); err == nil {
+		t.Error("Expected error when sorting a graph with a cycle, but got nil")
+	}
+}
+
 func TestDAGGetNodes(t *testing.T) {
 	d := NewDirectedAcyclicGraph()
 	d.AddVertex("A")
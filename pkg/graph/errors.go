@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single problem found while building a resource
+// group.
+type ValidationError struct {
+	// ResourceID is the id of the resource the error was found in. It is empty
+	// for errors that aren't specific to a resource, e.g. a cycle in the graph.
+	ResourceID string
+	// Path is the path of the field the error was found in, e.g
+	// "spec.vpcID" or "readyWhen[0]".
+	Path string
+	// Expression is the CEL expression that failed validation, if any.
+	Expression string
+	// Message is the description of the error.
+	Message string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// newValidationError creates a new ValidationError from an error.
+func newValidationError(resourceID, path, expression string, err error) *ValidationError {
+	return &ValidationError{
+		ResourceID: resourceID,
+		Path:       path,
+		Expression: expression,
+		Message:    err.Error(),
+		Err:        err,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if e.ResourceID != "" {
+		parts = append(parts, fmt.Sprintf("resource %s", e.ResourceID))
+	}
+	if e.Path != "" {
+		parts = append(parts, fmt.Sprintf("field %s", e.Path))
+	}
+	if e.Expression != "" {
+		parts = append(parts, fmt.Sprintf("expression '%s'", e.Expression))
+	}
+	parts = append(parts, e.Message)
+	return strings.Join(parts, ": ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is the list of errors found while building a resource
+// group. The builder keeps going after a failing resource or expression, to
+// report as many errors as possible at once.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("found %d validation errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// add appends an error to the list. Validation errors are appended as is,
+// other errors are attached to the given resource.
+func (e *ValidationErrors) add(resourceID string, err error) {
+	var validationErrs ValidationErrors
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErrs):
+		*e = append(*e, validationErrs...)
+	case errors.As(err, &validationErr):
+		*e = append(*e, validationErr)
+	default:
+		*e = append(*e, newValidationError(resourceID, "", "", err))
+	}
+}
+
+// errorOrNil returns nil if the list is empty, and the list otherwise.
+func (e ValidationErrors) errorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
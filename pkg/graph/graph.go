@@ -14,8 +14,11 @@
 package graph
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	krocel "github.com/awslabs/kro/pkg/cel"
 	"github.com/awslabs/kro/pkg/graph/dag"
 	"github.com/awslabs/kro/pkg/runtime"
 )
@@ -31,10 +34,17 @@ type Graph struct {
 	Resources map[string]*Resource
 	// TopologicalOrder is the topological order of the resources in the resource group.
 	TopologicalOrder []string
+	// TopologicalLevels groups the resources of the resource group in levels.
+	// The dependencies of a resource all sit in earlier levels, so resources of
+	// a same level can be processed together.
+	TopologicalLevels [][]string
+	// CELLimits are the limits applied to the expressions of the resource group
+	// when they are evaluated at runtime.
+	CELLimits krocel.Limits
 }
 
 // NewGraphRuntime creates a new runtime resource group from the resource group instance.
-func (rg *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runtime.ResourceGroupRuntime, error) {
+func (rg *Graph) NewGraphRuntime(ctx context.Context, newInstance *unstructured.Unstructured) (*runtime.ResourceGroupRuntime, error) {
 	// we need to copy the resources to the runtime resources, mainly focusing
 	// on the variables and dependencies.
 	resources := make(map[string]runtime.Resource)
@@ -44,7 +54,7 @@ func (rg *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runti
 
 	instance := rg.Instance.DeepCopy()
 	instance.originalObject = newInstance
-	rt, err := runtime.NewResourceGroupRuntime(instance, resources, rg.TopologicalOrder)
+	rt, err := runtime.NewResourceGroupRuntime(ctx, instance, resources, rg.TopologicalOrder, rg.CELLimits)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/awslabs/kro/pkg/runtime"
+)
+
+const xKubernetesIntOrString = "x-kubernetes-int-or-string"
+
+// ValidateInstance checks an instance against the graph before it gets
+// reconciled. It evaluates the static variables and the includeWhen
+// expressions that only depend on the instance, and checks the static values
+// against the schema of the fields they are set in. These errors would
+// otherwise only surface when the instance is reconciled.
+//
+// The returned error is a ValidationErrors listing every error found.
+func (rg *Graph) ValidateInstance(ctx context.Context, instance *unstructured.Unstructured) error {
+	rt, err := rg.NewGraphRuntime(ctx, instance)
+	if err != nil {
+		return ValidationErrors{newValidationError("", "spec", "", err)}
+	}
+
+	var errs ValidationErrors
+	for _, id := range rg.TopologicalOrder {
+		want, err := rt.WantToCreateResource(ctx, id)
+		var evalErr *runtime.EvalError
+		switch {
+		case errors.As(err, &evalErr) && evalErr.IsIncompleteData:
+			// The expressions depend on other resources, they can only be
+			// evaluated when the instance is reconciled.
+		case errors.As(err, &evalErr):
+			errs = append(errs, newValidationError(id, "includeWhen", "", evalErr.Err))
+			continue
+		case err != nil || !want:
+			// The resource is excluded, its values aren't used.
+			continue
+		}
+
+		for _, v := range rg.Resources[id].GetVariables() {
+			if !v.Kind.IsStatic() || !v.StandaloneExpression || v.ExpectedSchema == nil {
+				continue
+			}
+			expression := v.Expressions[0]
+			value, ok := rt.ExpressionValue(expression)
+			if !ok {
+				continue
+			}
+			if err := validateValue(v.Path, v.ExpectedSchema, value); err != nil {
+				errs = append(errs, newValidationError(id, v.Path, expression, err))
+			}
+		}
+	}
+	return errs.errorOrNil()
+}
+
+// validateValue validates a value against the schema of the field it is set
+// in.
+func validateValue(path string, schema *spec.Schema, value interface{}) error {
+	// The parser narrows down the type of int-or-string fields, the value
+	// can't be validated against it.
+	if intOrString, ok := schema.Extensions.GetBool(xKubernetesIntOrString); ok && intOrString {
+		return nil
+	}
+
+	result := validate.NewSchemaValidator(schema, nil, path, strfmt.Default).Validate(value)
+	if result.IsValid() {
+		return nil
+	}
+	messages := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		messages = append(messages, err.Error())
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
@@ -0,0 +1,171 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	krocel "github.com/awslabs/kro/pkg/cel"
+	"github.com/awslabs/kro/pkg/graph/emulator"
+	"github.com/awslabs/kro/pkg/testutil/generator"
+	"github.com/awslabs/kro/pkg/testutil/k8s"
+)
+
+func TestGraph_ValidateInstance(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	maxReplicas := float64(10)
+	fakeResolver.AddSchema(schema.GroupVersionKind{Group: "apps.example.com", Version: "v1", Kind: "App"}, &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"apiVersion": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"kind":       {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"metadata": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						Properties: map[string]spec.Schema{
+							"name": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+						},
+					},
+				},
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						Properties: map[string]spec.Schema{
+							"replicas": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Maximum: &maxReplicas}},
+							"tier":     {SchemaProps: spec.SchemaProps{Type: []string{"string"}, Enum: []interface{}{"web", "worker"}}},
+						},
+					},
+				},
+			},
+		},
+	})
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+		celLimits:        krocel.DefaultLimits(),
+	}
+
+	rg := generator.NewResourceGroup("test-group",
+		generator.WithSchema(
+			"Test", "v1alpha1",
+			map[string]interface{}{
+				"name":     "string",
+				"replicas": "integer",
+				"tier":     "string",
+				"zones":    "[]string",
+			},
+			nil,
+		),
+		generator.WithResource("app", map[string]interface{}{
+			"apiVersion": "apps.example.com/v1",
+			"kind":       "App",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"replicas": "${schema.spec.replicas}",
+				"tier":     "${schema.spec.tier}",
+			},
+		}, nil, nil),
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"cidrBlocks": []interface{}{"10.0.0.0/16"},
+			},
+		}, nil, []string{"${schema.spec.zones[0] == 'us-west-2a'}"}),
+	)
+	g, err := builder.NewResourceGroup(context.Background(), rg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		spec       map[string]interface{}
+		wantErrors []string
+	}{
+		{
+			name: "valid instance",
+			spec: map[string]interface{}{
+				"name":     "test",
+				"replicas": int64(3),
+				"tier":     "web",
+				"zones":    []interface{}{"us-west-2a", "us-west-2b"},
+			},
+		},
+		{
+			name: "static values out of the child schema",
+			spec: map[string]interface{}{
+				"name":     "test",
+				"replicas": int64(30),
+				"tier":     "cache",
+				"zones":    []interface{}{"us-west-2a", "us-west-2b"},
+			},
+			wantErrors: []string{
+				"resource app: field spec.replicas: expression 'schema.spec.replicas': spec.replicas in body should be less than or equal to 10",
+				"resource app: field spec.tier: expression 'schema.spec.tier': spec.tier in body should be one of [web worker]",
+			},
+		},
+		{
+			name: "failing includeWhen expression",
+			spec: map[string]interface{}{
+				"name":     "test",
+				"replicas": int64(3),
+				"tier":     "web",
+				"zones":    []interface{}{},
+			},
+			wantErrors: []string{
+				"resource vpc: field includeWhen: failed evaluating expression schema.spec.zones[0] == 'us-west-2a': index out of bounds: 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "Test",
+				"metadata": map[string]interface{}{
+					"name": "test",
+				},
+				"spec": tt.spec,
+			}}
+
+			err := g.ValidateInstance(context.Background(), instance)
+			if len(tt.wantErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var validationErrs ValidationErrors
+			require.True(t, errors.As(err, &validationErrs))
+			var got []string
+			for _, validationErr := range validationErrs {
+				got = append(got, validationErr.Error())
+			}
+			assert.ElementsMatch(t, tt.wantErrors, got)
+		})
+	}
+}
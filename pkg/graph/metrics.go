@@ -0,0 +1,77 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/awslabs/kro/pkg/tracing"
+)
+
+const (
+	// MetricBuildDuration tracks the duration of the resource group builds,
+	// by phase
+	MetricBuildDuration = "graph_build_duration_seconds"
+)
+
+// Phases of a resource group build. The schema resolution and emulation
+// phases are observed once per resource.
+const (
+	buildPhaseTotal            = "total"
+	buildPhaseDiscovery        = "discovery"
+	buildPhaseSchemaResolution = "schema_resolution"
+	buildPhaseEmulation        = "emulation"
+	buildPhaseCELDryRun        = "cel_dry_run"
+	buildPhaseDAG              = "dag"
+)
+
+// buildPhaseSpans are the names of the spans tracing the phases of a resource
+// group build.
+var buildPhaseSpans = map[string]string{
+	buildPhaseTotal:            "graph.NewResourceGroup",
+	buildPhaseDiscovery:        "graph.discovery",
+	buildPhaseSchemaResolution: "graph.schemaResolution",
+	buildPhaseEmulation:        "graph.emulation",
+	buildPhaseCELDryRun:        "graph.celDryRun",
+	buildPhaseDAG:              "graph.dag",
+}
+
+var buildDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    MetricBuildDuration,
+		Help:    "Duration of the resource group builds by phase",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
+	},
+	[]string{"phase"},
+)
+
+// startBuildPhase starts tracing and timing a phase of a resource group
+// build. The returned function ends the phase, recording its error if any.
+func startBuildPhase(ctx context.Context, phase string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, buildPhaseSpans[phase], attrs...)
+	return ctx, func(err error) {
+		buildDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+	}
+}
+
+func init() {
+	metrics.Registry.MustRegister(buildDuration)
+}
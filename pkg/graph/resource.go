@@ -15,12 +15,14 @@ package graph
 
 import (
 	"slices"
+	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 
+	"github.com/awslabs/kro/api/v1alpha1"
 	rgschema "github.com/awslabs/kro/pkg/graph/schema"
 	"github.com/awslabs/kro/pkg/graph/variable"
 )
@@ -60,12 +62,25 @@ type Resource struct {
 	variables []*variable.ResourceField
 	// dependencies is a list of the resources this resource depends on.
 	dependencies []string
+	// dependsOn is the list of resources this resource explicitly depends on,
+	// as declared by the user. They are merged into dependencies when building
+	// the dependency graph.
+	dependsOn []string
 	// readyWhenExpressions is a list of the expressions that need to be evaluated
 	// before the resource is considered ready.
 	readyWhenExpressions []string
 	// includeWhenExpressions is a list of the expresisons that need to be evaluated
 	// to decide whether to create a resource group or not
 	includeWhenExpressions []string
+	// readinessPolicy defines how the readiness of the resource is polled. It
+	// is nil when the resource uses the default polling.
+	readinessPolicy *v1alpha1.ReadinessPolicy
+	// failWhenExpressions is a list of the expressions marking the resource as
+	// failed when one of them evaluates to true.
+	failWhenExpressions []string
+	// readyTimeout is how long the resource is waited for before it is marked
+	// as failed. It is zero when the resource is waited for indefinitely.
+	readyTimeout time.Duration
 	// namespaced indicates if the resource is namespaced or cluster-scoped.
 	// This is useful when initiating the dynamic client to interact with the
 	// resource.
@@ -146,6 +161,23 @@ func (r *Resource) GetIncludeWhenExpressions() []string {
 	return r.includeWhenExpressions
 }
 
+// GetReadinessPolicy returns the readiness policy of the resource, nil if
+// the resource uses the default polling.
+func (r *Resource) GetReadinessPolicy() *v1alpha1.ReadinessPolicy {
+	return r.readinessPolicy
+}
+
+// GetFailWhenExpressions returns the failWhen expressions of the resource.
+func (r *Resource) GetFailWhenExpressions() []string {
+	return r.failWhenExpressions
+}
+
+// GetReadyTimeout returns the ready timeout of the resource, zero if the
+// resource is waited for indefinitely.
+func (r *Resource) GetReadyTimeout() time.Duration {
+	return r.readyTimeout
+}
+
 // GetTopLevelFields returns the top-level fields of the resource.
 func (r *Resource) GetTopLevelFields() []string {
 	return rgschema.GetResourceTopLevelFieldNames(r.schema)
@@ -165,8 +197,12 @@ func (r *Resource) DeepCopy() *Resource {
 		originalObject:         r.originalObject.DeepCopy(),
 		variables:              slices.Clone(r.variables),
 		dependencies:           slices.Clone(r.dependencies),
+		dependsOn:              slices.Clone(r.dependsOn),
 		readyWhenExpressions:   slices.Clone(r.readyWhenExpressions),
 		includeWhenExpressions: slices.Clone(r.includeWhenExpressions),
+		readinessPolicy:        r.readinessPolicy.DeepCopy(),
+		failWhenExpressions:    slices.Clone(r.failWhenExpressions),
+		readyTimeout:           r.readyTimeout,
 		namespaced:             r.namespaced,
 	}
 }
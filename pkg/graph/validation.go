@@ -86,16 +86,20 @@ func isKROReservedWord(word string) bool {
 }
 
 // validateResourceGroupNamingConventions validates the naming conventions of
-// the given resource group.
+// the given resource group. All the violations are reported at once.
 func validateResourceGroupNamingConventions(rg *v1alpha1.ResourceGroup) error {
+	var errs ValidationErrors
 	if !isValidKindName(rg.Spec.Schema.Kind) {
-		return fmt.Errorf("%s: kind '%s' is not a valid KRO kind name: must be UpperCamelCase", ErrNamingConvention, rg.Spec.Schema.Kind)
+		errs = append(errs, &ValidationError{
+			Path:    "spec.schema.kind",
+			Message: fmt.Sprintf("%s: kind '%s' is not a valid KRO kind name: must be UpperCamelCase", ErrNamingConvention, rg.Spec.Schema.Kind),
+		})
 	}
-	err := validateResourceIDs(rg)
-	if err != nil {
-		return fmt.Errorf("%s: %w", ErrNamingConvention, err)
+	for _, err := range validateResourceIDs(rg) {
+		err.Message = fmt.Sprintf("%s: %s", ErrNamingConvention, err.Message)
+		errs = append(errs, err)
 	}
-	return nil
+	return errs.errorOrNil()
 }
 
 // validateResource performs basic validation on a given resourcegroup.
@@ -106,23 +110,27 @@ func validateResourceGroupNamingConventions(rg *v1alpha1.ResourceGroup) error {
 // - The id should start with a lowercase letter.
 // - The id should only contain alphanumeric characters.
 // - does not contain any special characters, underscores, or hyphens.
-func validateResourceIDs(rg *v1alpha1.ResourceGroup) error {
+func validateResourceIDs(rg *v1alpha1.ResourceGroup) ValidationErrors {
+	var errs ValidationErrors
 	seen := make(map[string]struct{})
 	for _, res := range rg.Spec.Resources {
 		if isKROReservedWord(res.ID) {
-			return fmt.Errorf("id %s is a reserved keyword in KRO", res.ID)
+			errs = append(errs, newValidationError(res.ID, "id", "", fmt.Errorf("id %s is a reserved keyword in KRO", res.ID)))
+			continue
 		}
 
 		if !isValidResourceID(res.ID) {
-			return fmt.Errorf("id %s is not a valid KRO resource id: must be lower camelCase", res.ID)
+			errs = append(errs, newValidationError(res.ID, "id", "", fmt.Errorf("id %s is not a valid KRO resource id: must be lower camelCase", res.ID)))
+			continue
 		}
 
 		if _, ok := seen[res.ID]; ok {
-			return fmt.Errorf("found duplicate resource IDs %s", res.ID)
+			errs = append(errs, newValidationError(res.ID, "id", "", fmt.Errorf("found duplicate resource IDs %s", res.ID)))
+			continue
 		}
 		seen[res.ID] = struct{}{}
 	}
-	return nil
+	return errs
 }
 
 // validateKubernetesObjectStructure checks if the given object is a Kubernetes object.
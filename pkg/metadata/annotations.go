@@ -0,0 +1,81 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metadata
+
+import "github.com/awslabs/kro/api/v1alpha1"
+
+const (
+	// AnnotationKroPrefix is the prefix of the annotations understood by kro.
+	AnnotationKroPrefix = v1alpha1.KroDomainName + "/"
+)
+
+const (
+	// ServiceAccountAnnotation is set on an instance to request the service
+	// account used to manage its resources. The service account must live in
+	// the namespace of the instance, and be allowed by the resource group.
+	ServiceAccountAnnotation = AnnotationKroPrefix + "service-account"
+)
+
+const (
+	// AllowBreakingChangesAnnotation is set to "true" on a resource group to
+	// let kro update its CRD with breaking schema changes, e.g removed fields
+	// or type changes. Without it, such updates are refused.
+	AllowBreakingChangesAnnotation = AnnotationKroPrefix + "allow-breaking-changes"
+)
+
+// AllowsBreakingChanges returns true if the object opts in to breaking changes
+// of its CRD.
+func AllowsBreakingChanges(annotations map[string]string) bool {
+	return annotations[AllowBreakingChangesAnnotation] == "true"
+}
+
+const (
+	// ForceDeletionAnnotation is set to "true" on a resource group to let it
+	// be deleted while instances of its kind still exist. Without it, the
+	// deletion is held until all the instances are deleted.
+	ForceDeletionAnnotation = AnnotationKroPrefix + "force-deletion"
+)
+
+// ForcesDeletion returns true if the object opts in to being deleted while
+// it is still in use.
+func ForcesDeletion(annotations map[string]string) bool {
+	return annotations[ForceDeletionAnnotation] == "true"
+}
+
+const (
+	// ResourceGroupOwnerAnnotation is set on the CRDs generated by kro to the
+	// namespace/name of the resource group claiming their kind. A kind can
+	// only be claimed by a single resource group.
+	ResourceGroupOwnerAnnotation = AnnotationKroPrefix + "resource-group"
+)
+
+const (
+	// PausedAnnotation is set to "true" on an instance to freeze it, e.g during
+	// an incident or a maintenance window. The resources of a paused instance
+	// are neither created, updated nor deleted, only its status is updated.
+	// Set on a resource group, it pauses all of its instances.
+	PausedAnnotation = AnnotationKroPrefix + "paused"
+)
+
+// IsPaused returns true if the object is paused.
+func IsPaused(annotations map[string]string) bool {
+	return annotations[PausedAnnotation] == "true"
+}
+
+const (
+	// ReconcileIntervalAnnotation is set on an instance to override the
+	// interval at which it is reconciled again once active, e.g "10m". A zero
+	// interval disables the periodic reconciliation of the instance.
+	ReconcileIntervalAnnotation = AnnotationKroPrefix + "reconcile-interval"
+)
@@ -17,26 +17,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-
-	"github.com/awslabs/kro/api/v1alpha1"
-)
-
-var (
-	KRORGOwnerReferenceKind       = "ResourceGroup"
-	KRORGOwnerReferenceAPIVersion = v1alpha1.GroupVersion.String()
 )
 
-// stamped on the CRD and RGIs
-func NewResourceGroupOwnerReference(name string, uid types.UID) metav1.OwnerReference {
-	return metav1.OwnerReference{
-		Name:       name,
-		Kind:       KRORGOwnerReferenceKind,
-		APIVersion: KRORGOwnerReferenceAPIVersion,
-		Controller: &[]bool{false}[0],
-		UID:        uid,
-	}
-}
-
 // stamped on the RGI child resources
 func NewInstanceOwnerReference(gvk schema.GroupVersionKind, name string, uid types.UID) metav1.OwnerReference {
 	return metav1.OwnerReference{
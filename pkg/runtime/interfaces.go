@@ -14,9 +14,13 @@
 package runtime
 
 import (
+	"context"
+	"time"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/awslabs/kro/api/v1alpha1"
 	"github.com/awslabs/kro/pkg/graph/variable"
 )
 
@@ -33,7 +37,7 @@ type Interface interface {
 	// It returns true if the user should call Synchronize again, and false if all
 	// resources are resolved. An error is returned if the synchronization process
 	// encounters any issues.
-	Synchronize() (bool, error)
+	Synchronize(ctx context.Context) (bool, error)
 
 	// TopologicalOrder returns the topological order of resources.
 	TopologicalOrder() []string
@@ -59,11 +63,15 @@ type Interface interface {
 	SetInstance(obj *unstructured.Unstructured)
 
 	// IsResourceReady returns true if the resource is ready, and false otherwise.
-	IsResourceReady(resourceID string) (bool, string, error)
+	IsResourceReady(ctx context.Context, resourceID string) (bool, string, error)
+
+	// IsResourceFailed returns true if one of the failWhen expressions of the
+	// resource evaluates to true, with the reason why the resource failed.
+	IsResourceFailed(ctx context.Context, resourceID string) (bool, string, error)
 
 	// WantToCreateResource returns true if all the condition expressions return true
 	// if not it will add itself to the ignored resources
-	WantToCreateResource(resourceID string) (bool, error)
+	WantToCreateResource(ctx context.Context, resourceID string) (bool, error)
 
 	// IgnoreResource ignores resource that has a condition expressison that evaluated
 	// to false
@@ -109,6 +117,18 @@ type ResourceDescriptor interface {
 	// be evaluated before deciding whether to create a resource
 	GetIncludeWhenExpressions() []string
 
+	// GetReadinessPolicy returns the policy the readiness of the resource is
+	// polled with, nil if the resource uses the default polling.
+	GetReadinessPolicy() *v1alpha1.ReadinessPolicy
+
+	// GetFailWhenExpressions returns the list of expressions marking the
+	// resource as failed when one of them evaluates to true.
+	GetFailWhenExpressions() []string
+
+	// GetReadyTimeout returns how long the resource is waited for before it
+	// is marked as failed, zero if it is waited for indefinitely.
+	GetReadyTimeout() time.Duration
+
 	// GetTopLevelFields returns the list of top-level fields in the resource.
 	// e.g spec, status, metadata, etc.
 	GetTopLevelFields() []string
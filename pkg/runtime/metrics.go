@@ -0,0 +1,50 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricCELEvaluationDuration tracks the duration of the CEL expressions
+	// evaluated while reconciling instances
+	MetricCELEvaluationDuration = "cel_evaluation_duration_seconds"
+)
+
+var celEvaluationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: MetricCELEvaluationDuration,
+		Help: "Duration of the CEL expression evaluations by result",
+		// From 10µs to ~2.6s, expressions are usually cheap but the slow ones
+		// are bounded by the evaluation timeout.
+		Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+	},
+	[]string{"result"},
+)
+
+func observeCELEvaluation(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	celEvaluationDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+func init() {
+	metrics.Registry.MustRegister(celEvaluationDuration)
+}
@@ -14,15 +14,19 @@
 package runtime
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"golang.org/x/exp/maps"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	krocel "github.com/awslabs/kro/pkg/cel"
+	"github.com/awslabs/kro/pkg/cel/ast"
 	"github.com/awslabs/kro/pkg/graph/variable"
 	"github.com/awslabs/kro/pkg/runtime/resolver"
 )
@@ -42,14 +46,17 @@ var _ Interface = &ResourceGroupRuntime{}
 //
 // The output of this function is NOT thread safe.
 func NewResourceGroupRuntime(
+	ctx context.Context,
 	instance Resource,
 	resources map[string]Resource,
 	topologicalOrder []string,
+	celLimits krocel.Limits,
 ) (*ResourceGroupRuntime, error) {
 	r := &ResourceGroupRuntime{
 		instance:                     instance,
 		resources:                    resources,
 		topologicalOrder:             topologicalOrder,
+		celLimits:                    celLimits,
 		resolvedResources:            make(map[string]*unstructured.Unstructured),
 		runtimeVariables:             make(map[string][]*expressionEvaluationState),
 		expressionsCache:             make(map[string]*expressionEvaluationState),
@@ -109,7 +116,7 @@ func NewResourceGroupRuntime(
 
 	// Evaluate the static variables, so that the caller only needs to call Synchronize
 	// whenever a new resource is added or a variable is updated.
-	err := r.evaluateStaticVariables()
+	err := r.evaluateStaticVariables(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate static variables: %w", err)
 	}
@@ -166,6 +173,11 @@ type ResourceGroupRuntime struct {
 	// ignoredByConditionsResources holds the resources whos defined conditions returned false
 	// or who's dependencies are ignored
 	ignoredByConditionsResources map[string]bool
+
+	// celLimits bounds the cost and the duration of every expression evaluated
+	// by the runtime. A user authored comprehension over a large list should
+	// not be able to stall a controller worker.
+	celLimits krocel.Limits
 }
 
 // TopologicalOrder returns the topological order of resources.
@@ -227,7 +239,7 @@ func (rt *ResourceGroupRuntime) SetInstance(obj *unstructured.Unstructured) {
 // Every time Synchronize is called, it walks through the resources and tries
 // to resolve as many as possible. If a resource is resolved, it's added to the
 // resolved resources map.
-func (rt *ResourceGroupRuntime) Synchronize() (bool, error) {
+func (rt *ResourceGroupRuntime) Synchronize(ctx context.Context) (bool, error) {
 	// if everything is resolved, we're done.
 	// TODO(a-hilaly): Add readiness check here.
 	if rt.allExpressionsAreResolved() && len(rt.resolvedResources) == len(rt.resources) {
@@ -235,7 +247,7 @@ func (rt *ResourceGroupRuntime) Synchronize() (bool, error) {
 	}
 
 	// first synchronize the resources.
-	err := rt.evaluateDynamicVariables()
+	err := rt.evaluateDynamicVariables(ctx)
 	if err != nil {
 		return true, fmt.Errorf("failed to evaluate dynamic variables: %w", err)
 	}
@@ -301,7 +313,7 @@ func (rt *ResourceGroupRuntime) resourceVariablesResolved(resource string) bool
 // Static variables are those that can be evaluated immediately, typically
 // depending only on the initial configuration. This function is usually
 // called once during runtime initialization to set up the baseline state
-func (rt *ResourceGroupRuntime) evaluateStaticVariables() error {
+func (rt *ResourceGroupRuntime) evaluateStaticVariables(ctx context.Context) error {
 	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
 	if err != nil {
 		return err
@@ -312,7 +324,7 @@ func (rt *ResourceGroupRuntime) evaluateStaticVariables() error {
 	}
 	for _, variable := range rt.expressionsCache {
 		if variable.Kind.IsStatic() {
-			value, err := evaluateExpression(env, evalContext, variable.Expression)
+			value, err := evaluateExpression(ctx, env, rt.celLimits, evalContext, variable.Expression)
 			if err != nil {
 				return err
 			}
@@ -336,12 +348,16 @@ func (e *EvalError) Error() string {
 	return e.Err.Error()
 }
 
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
 // evaluateDynamicVariables processes all dynamic variables in the runtime.
 // Dynamic variables depend on the state of other resources and are evaluated
 // iteratively as resources are resolved. This function is called during each
 // synchronization cycle to update the runtime state based on newly resolved
 // resources.
-func (rt *ResourceGroupRuntime) evaluateDynamicVariables() error {
+func (rt *ResourceGroupRuntime) evaluateDynamicVariables(ctx context.Context) error {
 	// Dynamic variables are those that depend on other resources
 	// and are resolved after all the dependencies are resolved.
 
@@ -377,7 +393,7 @@ func (rt *ResourceGroupRuntime) evaluateDynamicVariables() error {
 
 			evalContext["schema"] = rt.instance.Unstructured().Object
 
-			value, err := evaluateExpression(env, evalContext, variable.Expression)
+			value, err := evaluateExpression(ctx, env, rt.celLimits, evalContext, variable.Expression)
 			if err != nil {
 				if strings.Contains(err.Error(), "no such key") {
 					// TODO(a-hilaly): I'm not sure if this is the best way to handle
@@ -462,7 +478,7 @@ func (rt *ResourceGroupRuntime) allExpressionsAreResolved() bool {
 // IsResourceReady checks if a resource is ready based on the readyWhenExpressions
 // defined in the resource. If no readyWhenExpressions are defined, the resource
 // is considered ready.
-func (rt *ResourceGroupRuntime) IsResourceReady(resourceID string) (bool, string, error) {
+func (rt *ResourceGroupRuntime) IsResourceReady(ctx context.Context, resourceID string) (bool, string, error) {
 	observed, ok := rt.resolvedResources[resourceID]
 	if !ok {
 		// Users need to make sure that the resource is resolved a.k.a (SetResource)
@@ -475,18 +491,24 @@ func (rt *ResourceGroupRuntime) IsResourceReady(resourceID string) (bool, string
 		return true, "", nil
 	}
 
+	// readyWhen expressions can refer to other resources, make sure they are
+	// resolved before evaluating the expressions.
+	if dependency, ok := rt.unresolvedDependency(resourceID); ok {
+		return false, fmt.Sprintf("dependency %s is not resolved", dependency), nil
+	}
+
 	// we should not expect errors here since we already compiled it
 	// in the dryRun
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{resourceID}))
+	env, context, err := rt.conditionEnvironment()
 	if err != nil {
 		return false, "", fmt.Errorf("failed creating new Environment: %w", err)
 	}
-	context := map[string]interface{}{
-		resourceID: observed.Object,
-	}
+	// The observed object is already part of the resolved resources, but
+	// let's be explicit about it.
+	context[resourceID] = observed.Object
 
 	for _, expression := range expressions {
-		out, err := evaluateExpression(env, context, expression)
+		out, err := evaluateExpression(ctx, env, rt.celLimits, context, expression)
 		if err != nil {
 			return false, "", fmt.Errorf("failed evaluating expressison %s: %w", expression, err)
 		}
@@ -498,6 +520,89 @@ func (rt *ResourceGroupRuntime) IsResourceReady(resourceID string) (bool, string
 	return true, "", nil
 }
 
+// IsResourceFailed checks if a resource failed based on the failWhen expressions
+// defined in the resource. The reason names the expression that evaluated to
+// true, and the values of the fields it refers to. A resource without failWhen
+// expressions never fails.
+func (rt *ResourceGroupRuntime) IsResourceFailed(ctx context.Context, resourceID string) (bool, string, error) {
+	observed, ok := rt.resolvedResources[resourceID]
+	if !ok {
+		return false, "", nil
+	}
+
+	expressions := rt.resources[resourceID].GetFailWhenExpressions()
+	if len(expressions) == 0 {
+		return false, "", nil
+	}
+
+	// failWhen expressions can refer to other resources, a resource can't be
+	// failed before they are resolved.
+	if _, ok := rt.unresolvedDependency(resourceID); ok {
+		return false, "", nil
+	}
+
+	env, context, err := rt.conditionEnvironment()
+	if err != nil {
+		return false, "", fmt.Errorf("failed creating new Environment: %w", err)
+	}
+	context[resourceID] = observed.Object
+
+	for _, expression := range expressions {
+		out, err := evaluateExpression(ctx, env, rt.celLimits, context, expression)
+		if err != nil {
+			return false, "", fmt.Errorf("failed evaluating expression %s: %w", expression, err)
+		}
+		if out.(bool) {
+			reason := fmt.Sprintf("failWhen expression %s evaluated to true", expression)
+			if values := rt.observedValues(ctx, env, context, expression); len(values) > 0 {
+				reason = fmt.Sprintf("%s, observed %s", reason, strings.Join(values, ", "))
+			}
+			return true, reason, nil
+		}
+	}
+	return false, "", nil
+}
+
+// observedValues returns the values of the fields an expression refers to,
+// formatted as field=value. Fields that can't be evaluated are left out.
+func (rt *ResourceGroupRuntime) observedValues(ctx context.Context, env *cel.Env, context map[string]interface{}, expression string) []string {
+	inspection, err := ast.NewInspectorWithEnv(env, maps.Keys(context), nil).Inspect(expression)
+	if err != nil {
+		return nil
+	}
+
+	var values []string
+	seen := map[string]bool{}
+	for _, dependency := range inspection.ResourceDependencies {
+		if seen[dependency.Path] {
+			continue
+		}
+		seen[dependency.Path] = true
+
+		value, err := evaluateExpression(ctx, env, rt.celLimits, context, dependency.Path)
+		if err != nil {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s=%s", dependency.Path, encoded))
+	}
+	return values
+}
+
+// ExpressionValue returns the value an expression was resolved to, and true if
+// the expression is resolved. Static expressions are resolved as soon as the
+// runtime is created.
+func (rt *ResourceGroupRuntime) ExpressionValue(expression string) (interface{}, bool) {
+	ees, ok := rt.expressionsCache[expression]
+	if !ok || !ees.Resolved {
+		return nil, false
+	}
+	return ees.ResolvedValue, true
+}
+
 // IgnoreResource ignores resource that has a conditions expressison that evaluated
 // to false or whose dependencies are ignored
 func (rt *ResourceGroupRuntime) IgnoreResource(resourceID string) {
@@ -521,7 +626,7 @@ func (rt *ResourceGroupRuntime) areDependenciesIgnored(resourceID string) bool {
 
 // WantToCreateResource returns true if all the condition expressions return true
 // if not it will add itself to the ignored resources
-func (rt *ResourceGroupRuntime) WantToCreateResource(resourceID string) (bool, error) {
+func (rt *ResourceGroupRuntime) WantToCreateResource(ctx context.Context, resourceID string) (bool, error) {
 	if rt.areDependenciesIgnored(resourceID) {
 		return false, nil
 	}
@@ -531,22 +636,27 @@ func (rt *ResourceGroupRuntime) WantToCreateResource(resourceID string) (bool, e
 		return true, nil
 	}
 
+	// includeWhen expressions can refer to other resources, they can only be
+	// evaluated once those resources are resolved.
+	if dependency, ok := rt.unresolvedDependency(resourceID); ok {
+		return false, &EvalError{
+			IsIncompleteData: true,
+			Err:              fmt.Errorf("dependency %s is not resolved", dependency),
+		}
+	}
+
 	// we should not expect errors here since we already compiled it
 	// in the dryRun
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	env, context, err := rt.conditionEnvironment()
 	if err != nil {
-		return false, nil
-	}
-
-	context := map[string]interface{}{
-		"schema": rt.instance.Unstructured().Object,
+		return false, fmt.Errorf("failed creating new Environment: %w", err)
 	}
 
 	for _, condition := range conditions {
 		// We should not expect an error here as well since we checked during dry-run
-		value, err := evaluateExpression(env, context, condition)
+		value, err := evaluateExpression(ctx, env, rt.celLimits, context, condition)
 		if err != nil {
-			return false, err
+			return false, &EvalError{Err: err}
 		}
 		// returning a reason here to point out which expression is not ready yet
 		if !value.(bool) {
@@ -556,21 +666,55 @@ func (rt *ResourceGroupRuntime) WantToCreateResource(resourceID string) (bool, e
 	return true, nil
 }
 
-// evaluateExpression evaluates an CEL expression and returns a value if successful, or error
-func evaluateExpression(env *cel.Env, context map[string]interface{}, expression string) (interface{}, error) {
+// unresolvedDependency returns the first dependency of the resource that is
+// not resolved yet, and true if there is one.
+func (rt *ResourceGroupRuntime) unresolvedDependency(resourceID string) (string, bool) {
+	for _, dependency := range rt.resources[resourceID].GetDependencies() {
+		if _, ok := rt.resolvedResources[dependency]; !ok {
+			return dependency, true
+		}
+	}
+	return "", false
+}
+
+// conditionEnvironment returns the CEL environment and the context used to
+// evaluate readyWhen and includeWhen expressions. Both are made of the instance
+// (exposed as "schema") and the resolved resources.
+func (rt *ResourceGroupRuntime) conditionEnvironment() (*cel.Env, map[string]interface{}, error) {
+	resourceIDs := []string{"schema"}
+	context := map[string]interface{}{
+		"schema": rt.instance.Unstructured().Object,
+	}
+	for id, resource := range rt.resolvedResources {
+		resourceIDs = append(resourceIDs, id)
+		context[id] = resource.Object
+	}
+
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+	return env, context, nil
+}
+
+// evaluateExpression evaluates an CEL expression and returns a value if successful, or error.
+// The evaluation is interrupted if it exceeds the given limits.
+func evaluateExpression(ctx context.Context, env *cel.Env, limits krocel.Limits, vars map[string]interface{}, expression string) (interface{}, error) {
 	ast, issues := env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("failed compiling expression %s: %w", expression, issues.Err())
 	}
 	// Here as well
-	program, err := env.Program(ast)
+	program, err := env.Program(ast, limits.ProgramOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed programming expression %s: %w", expression, err)
 	}
 	// We get an error here when the value field we're looking for is not yet defined
 	// For now leaving it as error, in the future when we see different scenarios
 	// of this error we can make some a reason, and others an error
-	val, _, err := program.Eval(context)
+	start := time.Now()
+	val, err := limits.Eval(ctx, program, vars)
+	observeCELEvaluation(start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed evaluating expression %s: %w", expression, err)
 	}
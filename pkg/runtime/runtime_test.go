@@ -14,15 +14,19 @@
 package runtime
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/awslabs/kro/api/v1alpha1"
 	krocel "github.com/awslabs/kro/pkg/cel"
 	"github.com/awslabs/kro/pkg/graph/variable"
 )
@@ -212,13 +216,13 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	}
 
 	// 2. Create runtime
-	rt, err := NewResourceGroupRuntime(instance, resources, []string{"configmap", "secret", "deployment", "service"})
+	rt, err := NewResourceGroupRuntime(context.Background(), instance, resources, []string{"configmap", "secret", "deployment", "service"}, krocel.DefaultLimits())
 	if err != nil {
 		t.Fatalf("NewResourceGroupRuntime() error = %v", err)
 	}
 
 	// 3. First sync - should resolve static variables
-	cont, err := rt.Synchronize()
+	cont, err := rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("First Synchronize() error = %v", err)
 	}
@@ -252,7 +256,7 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	})
 
 	// 5. Second sync - should resolve Secret's dynamic variables
-	cont, err = rt.Synchronize()
+	cont, err = rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("Second Synchronize() error = %v", err)
 	}
@@ -279,7 +283,7 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	})
 
 	// 7. Third sync - should resolve Deployments dynamic variables
-	cont, err = rt.Synchronize()
+	cont, err = rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("Third Synchronize() error = %v", err)
 	}
@@ -305,7 +309,7 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	})
 
 	// 8. Fourth sync - should resolve the SVC dynamic variables
-	cont, err = rt.Synchronize()
+	cont, err = rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("Fourth Synchronize() error = %v", err)
 	}
@@ -334,7 +338,7 @@ func Test_RuntimeWorkflow(t *testing.T) {
 	})
 
 	// 10. Final sync - should resolve instance status
-	cont, err = rt.Synchronize()
+	cont, err = rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("Final Synchronize() error = %v", err)
 	}
@@ -347,7 +351,7 @@ func Test_RuntimeWorkflow(t *testing.T) {
 		t.Error("Instance status not properly updated")
 	}
 
-	cont, err = rt.Synchronize()
+	cont, err = rt.Synchronize(context.Background())
 	if err != nil {
 		t.Fatalf("Final Synchronize() error = %v", err)
 	}
@@ -439,7 +443,7 @@ func Test_NewResourceGroupRuntime(t *testing.T) {
 		"service":    service,
 	}
 
-	rt, err := NewResourceGroupRuntime(instance, resources, []string{"deployment", "service"})
+	rt, err := NewResourceGroupRuntime(context.Background(), instance, resources, []string{"deployment", "service"}, krocel.DefaultLimits())
 	if err != nil {
 		t.Fatalf("NewResourceGroupRuntime() error = %v", err)
 	}
@@ -780,7 +784,7 @@ func Test_Synchronize(t *testing.T) {
 				runtimeVariables:  tt.runtimeVariables,
 			}
 
-			gotContinue, err := rt.Synchronize()
+			gotContinue, err := rt.Synchronize(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Synchronize() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1441,7 +1445,7 @@ func Test_evaluateStaticVariables(t *testing.T) {
 				expressionsCache: tt.expressionsCache,
 			}
 
-			err := rt.evaluateStaticVariables()
+			err := rt.evaluateStaticVariables(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("evaluateStaticVariables() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1697,7 +1701,7 @@ func Test_evaluateDynamicVariables(t *testing.T) {
 				resolvedResources: tt.resolvedResources,
 			}
 
-			err := rt.evaluateDynamicVariables()
+			err := rt.evaluateDynamicVariables(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("evaluateDynamicVariables() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -2171,6 +2175,8 @@ func Test_IsResourceReady(t *testing.T) {
 		name           string
 		resource       Resource
 		resolvedObject map[string]interface{}
+		instanceSpec   map[string]interface{}
+		otherResolved  map[string]map[string]interface{}
 		want           bool
 		wantReason     string
 		wantErr        bool
@@ -2253,11 +2259,62 @@ func Test_IsResourceReady(t *testing.T) {
 			want:       false,
 			wantReason: "expression test.status.healthy evaluated to false",
 		},
+		{
+			name: "expression referencing the instance spec",
+			resource: newTestResource(
+				withReadyExpressions([]string{"test.status.replicas == schema.spec.replicas"}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"replicas": 3,
+				},
+			},
+			instanceSpec: map[string]interface{}{
+				"replicas": 3,
+			},
+			want: true,
+		},
+		{
+			name: "expression referencing another resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withReadyExpressions([]string{"test.status.endpoint == dep1.status.endpoint"}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"endpoint": "10.0.0.1",
+				},
+			},
+			otherResolved: map[string]map[string]interface{}{
+				"dep1": {
+					"status": map[string]interface{}{
+						"endpoint": "10.0.0.2",
+					},
+				},
+			},
+			want:       false,
+			wantReason: "expression test.status.endpoint == dep1.status.endpoint evaluated to false",
+		},
+		{
+			name: "expression referencing an unresolved resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withReadyExpressions([]string{"dep1.status.ready"}),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+			wantReason:     "dependency dep1 is not resolved",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rt := &ResourceGroupRuntime{
+				instance: newTestResource(
+					withObject(map[string]interface{}{
+						"spec": tt.instanceSpec,
+					}),
+				),
 				resources:         map[string]Resource{"test": tt.resource},
 				resolvedResources: map[string]*unstructured.Unstructured{},
 			}
@@ -2265,8 +2322,11 @@ func Test_IsResourceReady(t *testing.T) {
 			if tt.resolvedObject != nil {
 				rt.resolvedResources["test"] = &unstructured.Unstructured{Object: tt.resolvedObject}
 			}
+			for id, obj := range tt.otherResolved {
+				rt.resolvedResources[id] = &unstructured.Unstructured{Object: obj}
+			}
 
-			got, reason, err := rt.IsResourceReady("test")
+			got, reason, err := rt.IsResourceReady(context.Background(), "test")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("IsResourceReady() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -2280,15 +2340,153 @@ func Test_IsResourceReady(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsResourceFailed(t *testing.T) {
+	tests := []struct {
+		name           string
+		resource       Resource
+		resolvedObject map[string]interface{}
+		otherResolved  map[string]map[string]interface{}
+		want           bool
+		wantReason     string
+		wantErr        bool
+	}{
+		{
+			name: "no fail expressions",
+			resource: newTestResource(
+				withFailExpressions(nil),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+		},
+		{
+			name: "resource not resolved",
+			resource: newTestResource(
+				withFailExpressions([]string{"test.status.failed"}),
+			),
+			want: false,
+		},
+		{
+			name: "fail expression false",
+			resource: newTestResource(
+				withFailExpressions([]string{`test.status.status == "FAILED"`}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"status": "CREATING",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "fail expression true",
+			resource: newTestResource(
+				withFailExpressions([]string{`test.status.status == "FAILED"`}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"status": "FAILED",
+				},
+			},
+			want:       true,
+			wantReason: `failWhen expression test.status.status == "FAILED" evaluated to true, observed test.status.status="FAILED"`,
+		},
+		{
+			name: "multiple expressions one true",
+			resource: newTestResource(
+				withFailExpressions([]string{"test.status.failed", "test.status.retries > 3 && test.status.phase != 'Running'"}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"failed":  false,
+					"retries": 5,
+					"phase":   "Pending",
+				},
+			},
+			want:       true,
+			wantReason: `failWhen expression test.status.retries > 3 && test.status.phase != 'Running' evaluated to true, observed test.status.retries=5, test.status.phase="Pending"`,
+		},
+		{
+			name: "expression referencing another resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withFailExpressions([]string{"test.status.endpoint != dep1.status.endpoint"}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"endpoint": "10.0.0.1",
+				},
+			},
+			otherResolved: map[string]map[string]interface{}{
+				"dep1": {
+					"status": map[string]interface{}{
+						"endpoint": "10.0.0.2",
+					},
+				},
+			},
+			want:       true,
+			wantReason: `failWhen expression test.status.endpoint != dep1.status.endpoint evaluated to true, observed test.status.endpoint="10.0.0.1", dep1.status.endpoint="10.0.0.2"`,
+		},
+		{
+			name: "expression referencing an unresolved resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withFailExpressions([]string{"dep1.status.failed"}),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+		},
+		{
+			name: "missing field",
+			resource: newTestResource(
+				withFailExpressions([]string{"test.status.failed"}),
+			),
+			resolvedObject: map[string]interface{}{},
+			want:           false,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ResourceGroupRuntime{
+				instance:          newTestResource(),
+				resources:         map[string]Resource{"test": tt.resource},
+				resolvedResources: map[string]*unstructured.Unstructured{},
+			}
+
+			if tt.resolvedObject != nil {
+				rt.resolvedResources["test"] = &unstructured.Unstructured{Object: tt.resolvedObject}
+			}
+			for id, obj := range tt.otherResolved {
+				rt.resolvedResources[id] = &unstructured.Unstructured{Object: obj}
+			}
+
+			got, reason, err := rt.IsResourceFailed(context.Background(), "test")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsResourceFailed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsResourceFailed() = %v, want %v", got, tt.want)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("IsResourceFailed() reason = %v, want %v", reason, tt.wantReason)
+			}
+		})
+	}
+}
 func Test_WantToCreateResource(t *testing.T) {
 	tests := []struct {
 		name         string
 		resource     Resource
 		instanceSpec map[string]interface{}
 		ignoredDeps  map[string]bool
+		resolvedDeps map[string]map[string]interface{}
 		want         bool
 		wantSkip     bool
 		wantErr      bool
+		wantPending  bool
 	}{
 		{
 			name: "no conditions",
@@ -2352,6 +2550,32 @@ func Test_WantToCreateResource(t *testing.T) {
 			want:     false,
 			wantSkip: true,
 		},
+		{
+			name: "condition referencing a resolved resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withConditions([]string{"dep1.status.ready && schema.spec.enabled"}),
+			),
+			instanceSpec: map[string]interface{}{
+				"enabled": true,
+			},
+			resolvedDeps: map[string]map[string]interface{}{
+				"dep1": {
+					"status": map[string]interface{}{
+						"ready": true,
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "condition referencing an unresolved resource",
+			resource: newTestResource(
+				withDependencies([]string{"dep1"}),
+				withConditions([]string{"dep1.status.ready"}),
+			),
+			wantPending: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2366,9 +2590,20 @@ func Test_WantToCreateResource(t *testing.T) {
 				resources: map[string]Resource{
 					"test": tt.resource,
 				},
+				resolvedResources: map[string]*unstructured.Unstructured{},
+			}
+			for id, obj := range tt.resolvedDeps {
+				rt.resolvedResources[id] = &unstructured.Unstructured{Object: obj}
 			}
 
-			got, err := rt.WantToCreateResource("test")
+			got, err := rt.WantToCreateResource(context.Background(), "test")
+			if tt.wantPending {
+				var evalErr *EvalError
+				if !errors.As(err, &evalErr) || !evalErr.IsIncompleteData {
+					t.Errorf("WantToCreateResource() expected incomplete data error, got %v", err)
+				}
+				return
+			}
 			if tt.wantErr {
 				if err == nil {
 					t.Error("WantToCreateResource() expected error, got none")
@@ -2478,6 +2713,7 @@ func Test_evaluateExpression(t *testing.T) {
 	tests := []struct {
 		name       string
 		context    map[string]interface{}
+		limits     krocel.Limits
 		expression string
 		want       interface{}
 		wantErr    bool
@@ -2510,11 +2746,33 @@ func Test_evaluateExpression(t *testing.T) {
 			expression: "undefined.value",
 			wantErr:    true,
 		},
+		{
+			name: "comprehension within cost limit",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{1, 2, 3},
+				},
+			},
+			limits:     krocel.DefaultLimits(),
+			expression: "data.items.map(x, x * 2).size()",
+			want:       int64(3),
+		},
+		{
+			name: "comprehension exceeding cost limit",
+			context: map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{1, 2, 3},
+				},
+			},
+			limits:     krocel.Limits{CostLimit: 10},
+			expression: "data.items.map(x, data.items.map(y, x * y)).size()",
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := evaluateExpression(env, tt.context, tt.expression)
+			got, err := evaluateExpression(context.Background(), env, tt.limits, tt.context, tt.expression)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("evaluateExpression() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -2526,6 +2784,59 @@ func Test_evaluateExpression(t *testing.T) {
 	}
 }
 
+func Test_evaluateExpressionInterrupted(t *testing.T) {
+	env, err := setupTestEnv([]string{"data"})
+	if err != nil {
+		t.Fatalf("failed to create environment: %v", err)
+	}
+
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = i
+	}
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{
+			"items": items,
+		},
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		limits       krocel.Limits
+		wantErr      error
+		wantLimitErr bool
+	}{
+		{
+			name:    "cancelled by the caller",
+			ctx:     cancelled,
+			limits:  krocel.Limits{},
+			wantErr: context.Canceled,
+		},
+		{
+			name:         "evaluation timeout",
+			ctx:          context.Background(),
+			limits:       krocel.Limits{EvaluationTimeout: time.Nanosecond},
+			wantErr:      krocel.ErrEvaluationTimeout,
+			wantLimitErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evaluateExpression(tt.ctx, env, tt.limits, vars, "data.items.map(x, data.items.map(y, x * y)).size()")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("evaluateExpression() error = %v, want %v", err, tt.wantErr)
+			}
+			if krocel.IsLimitError(err) != tt.wantLimitErr {
+				t.Errorf("IsLimitError() = %v, want %v", krocel.IsLimitError(err), tt.wantLimitErr)
+			}
+		})
+	}
+}
+
 func Test_containsAllElements(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -2610,6 +2921,7 @@ type mockResource struct {
 	variables        []*variable.ResourceField
 	dependencies     []string
 	readyExpressions []string
+	failExpressions  []string
 	conditions       []string
 	topLevelFields   []string
 	namespaced       bool
@@ -2644,6 +2956,18 @@ func (m *mockResource) GetIncludeWhenExpressions() []string {
 	return m.conditions
 }
 
+func (m *mockResource) GetReadinessPolicy() *v1alpha1.ReadinessPolicy {
+	return nil
+}
+
+func (m *mockResource) GetFailWhenExpressions() []string {
+	return m.failExpressions
+}
+
+func (m *mockResource) GetReadyTimeout() time.Duration {
+	return 0
+}
+
 func (m *mockResource) GetTopLevelFields() []string {
 	return m.topLevelFields
 }
@@ -2686,6 +3010,12 @@ func withReadyExpressions(exprs []string) mockResourceOption {
 	}
 }
 
+func withFailExpressions(exprs []string) mockResourceOption {
+	return func(m *mockResource) {
+		m.failExpressions = exprs
+	}
+}
+
 func withConditions(conditions []string) mockResourceOption {
 	return func(m *mockResource) {
 		m.conditions = conditions
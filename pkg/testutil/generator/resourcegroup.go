@@ -98,3 +98,16 @@ func WithResource(
 		})
 	}
 }
+
+// WithResourceFields mutates the resource with the given id, e.g to set its
+// dependencies or its readiness policy. It must be used after the resource is
+// added with WithResource.
+func WithResourceFields(id string, mutate func(*krov1alpha1.Resource)) ResourceGroupOption {
+	return func(rg *krov1alpha1.ResourceGroup) {
+		for _, resource := range rg.Spec.Resources {
+			if resource.ID == id {
+				mutate(resource)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tracing configures the OpenTelemetry tracer used by kro to trace
+// resource group builds, instance reconciliations and the operations made on
+// the resources of an instance.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer used by kro.
+	TracerName = "github.com/awslabs/kro"
+	// DefaultServiceName is the service name spans are reported under.
+	DefaultServiceName = "kro"
+	// DefaultSampleRatio is the ratio of traces sampled when none is provided.
+	DefaultSampleRatio = 1.0
+)
+
+// Attribute keys set on the spans created by kro. Spans of the same instance
+// reconciliation all carry the instance UID, so that they can be looked up
+// together.
+const (
+	ResourceGroupNameKey = attribute.Key("kro.resourcegroup.name")
+	InstanceUIDKey       = attribute.Key("kro.instance.uid")
+	InstanceNameKey      = attribute.Key("kro.instance.name")
+	InstanceNamespaceKey = attribute.Key("kro.instance.namespace")
+	ResourceIDKey        = attribute.Key("kro.resource.id")
+	ResourceGVRKey       = attribute.Key("kro.resource.gvr")
+	ResourceNameKey      = attribute.Key("kro.resource.name")
+)
+
+// Config holds the configuration of the span exporter.
+type Config struct {
+	// Endpoint is the address of the OTLP gRPC collector spans are exported
+	// to. Tracing is disabled when empty.
+	Endpoint string
+	// Insecure disables the transport security of the connection to the
+	// collector.
+	Insecure bool
+	// ServiceName is the service name spans are reported under.
+	ServiceName string
+	// SampleRatio is the ratio of traces sampled, between 0 and 1.
+	SampleRatio float64
+}
+
+// Setup configures the global tracer provider to export spans over OTLP. When
+// no endpoint is configured the global no-op tracer provider is left in place
+// and spans are discarded. The returned function flushes the pending spans and
+// shuts down the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = DefaultServiceName
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample ratio %v, must be between 0 and 1", cfg.SampleRatio)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer used by kro. It is resolved from the global tracer
+// provider, which is a no-op unless Setup configured an exporter.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start starts a new span with the given name and attributes, as a child of
+// the span in ctx if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
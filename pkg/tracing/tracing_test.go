@@ -0,0 +1,64 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	tracingtest "github.com/awslabs/kro/pkg/testutil/tracing"
+	"github.com/awslabs/kro/pkg/tracing"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("no endpoint keeps the no-op tracer provider", func(t *testing.T) {
+		previous := otel.GetTracerProvider()
+		shutdown, err := tracing.Setup(context.Background(), tracing.Config{})
+		require.NoError(t, err)
+		assert.Equal(t, previous, otel.GetTracerProvider())
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("invalid sample ratio", func(t *testing.T) {
+		_, err := tracing.Setup(context.Background(), tracing.Config{
+			Endpoint:    "localhost:4317",
+			SampleRatio: 2,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestStartEnd(t *testing.T) {
+	exporter := tracingtest.NewInMemoryExporter(t)
+
+	ctx, parent := tracing.Start(context.Background(), "parent", tracing.InstanceUIDKey.String("uid"))
+	_, child := tracing.Start(ctx, "child")
+	tracing.End(child, errors.New("boom"))
+	tracing.End(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, []string{"child", "parent"}, tracingtest.SpanNames(exporter))
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "boom", spans[0].Status.Description)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	assert.Contains(t, spans[1].Attributes, tracing.InstanceUIDKey.String("uid"))
+}
@@ -0,0 +1,216 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/graph"
+)
+
+// InstanceValidationPath is the path the instance validating webhook is
+// served on. A single webhook serves the instances of every resource group.
+const InstanceValidationPath = "/validate-kro-run-instance"
+
+// InstanceValidator validates the instances of resource groups on admission.
+// It evaluates the static variables and the includeWhen expressions of the
+// resource group against the instance, and checks the static values against
+// the schemas of the resources they are set in. Instances failing these checks
+// would otherwise only fail when reconciled.
+type InstanceValidator struct {
+	client  client.Reader
+	builder resourceGroupBuilder
+
+	mu sync.Mutex
+	// graphs caches the graphs of the resource groups, keyed by resource
+	// group UID. Graphs are rebuilt when the generation of their resource
+	// group changes.
+	graphs map[types.UID]cachedGraph
+}
+
+type cachedGraph struct {
+	generation int64
+	graph      *graph.Graph
+}
+
+var _ admission.Handler = &InstanceValidator{}
+
+// NewInstanceValidator creates a new InstanceValidator.
+func NewInstanceValidator(client client.Reader, builder *graph.Builder) *InstanceValidator {
+	return &InstanceValidator{
+		client:  client,
+		builder: builder,
+		graphs:  make(map[types.UID]cachedGraph),
+	}
+}
+
+// SetupWithManager registers the validator with the webhook server of the
+// manager.
+func (v *InstanceValidator) SetupWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(InstanceValidationPath, &webhook.Admission{Handler: v})
+	return nil
+}
+
+// Handle validates the instance of an admission request. Requests for objects
+// that aren't instances of an active resource group are allowed.
+func (v *InstanceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	instance := &unstructured.Unstructured{}
+	if err := instance.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// Instances being deleted only get their finalizers removed.
+	if instance.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	// Updates that don't touch the spec, e.g the finalizers and labels set by
+	// the instance controller, don't need to be validated again.
+	if req.Operation == admissionv1.Update {
+		oldInstance := &unstructured.Unstructured{}
+		if err := oldInstance.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(oldInstance.Object["spec"], instance.Object["spec"]) {
+			return admission.Allowed("")
+		}
+	}
+
+	rg, err := v.resourceGroupFor(ctx, req.Kind.Kind, req.Kind.Version)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if rg == nil {
+		return admission.Allowed("")
+	}
+	g, err := v.graphFor(ctx, rg)
+	if err != nil {
+		// The resource group itself is invalid, its controller reports the
+		// error and doesn't reconcile the instances anyway.
+		return admission.Allowed("")
+	}
+
+	err = g.ValidateInstance(ctx, instance)
+	if err == nil {
+		return admission.Allowed("")
+	}
+	var validationErrs graph.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	errs := make(field.ErrorList, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		errs = append(errs, toInstanceFieldError(validationErr))
+	}
+	statusErr := apierrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, instance.GetName(), errs)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		},
+	}
+}
+
+// resourceGroupFor returns the resource group serving the given kind and
+// version, or nil if there is none. The cached graphs of the resource groups
+// that don't exist anymore are dropped.
+func (v *InstanceValidator) resourceGroupFor(ctx context.Context, kind, version string) (*v1alpha1.ResourceGroup, error) {
+	var rgs v1alpha1.ResourceGroupList
+	if err := v.client.List(ctx, &rgs); err != nil {
+		return nil, fmt.Errorf("failed to list resource groups: %w", err)
+	}
+	v.pruneGraphs(rgs.Items)
+
+	for i := range rgs.Items {
+		rg := &rgs.Items[i]
+		// Resource groups in conflict don't serve the kind they define.
+		if rg.Spec.Schema == nil || rg.DeletionTimestamp != nil || rg.Status.State == v1alpha1.ResourceGroupStateConflict {
+			continue
+		}
+		if rg.Spec.Schema.Kind == kind && rg.Spec.Schema.APIVersion == version {
+			return rg, nil
+		}
+	}
+	return nil, nil
+}
+
+// graphFor returns the graph of a resource group, building it if the cached
+// one is missing or stale.
+func (v *InstanceValidator) graphFor(ctx context.Context, rg *v1alpha1.ResourceGroup) (*graph.Graph, error) {
+	v.mu.Lock()
+	cached, ok := v.graphs[rg.UID]
+	v.mu.Unlock()
+	if ok && cached.generation == rg.Generation {
+		return cached.graph, nil
+	}
+
+	g, err := v.builder.NewResourceGroup(ctx, rg)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.graphs[rg.UID] = cachedGraph{generation: rg.Generation, graph: g}
+	return g, nil
+}
+
+// pruneGraphs drops the cached graphs of the resource groups that aren't in
+// the given list.
+func (v *InstanceValidator) pruneGraphs(rgs []v1alpha1.ResourceGroup) {
+	uids := make(map[types.UID]bool, len(rgs))
+	for _, rg := range rgs {
+		uids[rg.UID] = true
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for uid := range v.graphs {
+		if !uids[uid] {
+			delete(v.graphs, uid)
+		}
+	}
+}
+
+// toInstanceFieldError converts an instance validation error to a field error.
+// The errors are reported under the instance spec, the resource and the field
+// the failing value is set in are part of the message.
+func toInstanceFieldError(err *graph.ValidationError) *field.Error {
+	var value interface{} = field.OmitValueType{}
+	if err.Expression != "" {
+		value = err.Expression
+	}
+	detail := *err
+	detail.Expression = ""
+	return field.Invalid(field.NewPath("spec"), value, detail.Error())
+}
@@ -0,0 +1,137 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/graph"
+)
+
+func newInstanceRequest(t *testing.T, operation admissionv1.Operation, spec, oldSpec map[string]interface{}, deleting bool) admission.Request {
+	raw := func(spec map[string]interface{}) []byte {
+		metadata := map[string]interface{}{"name": "test"}
+		if deleting {
+			metadata["deletionTimestamp"] = "2024-01-01T00:00:00Z"
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "WebApp",
+			"metadata":   metadata,
+			"spec":       spec,
+		})
+		require.NoError(t, err)
+		return data
+	}
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		Kind:      metav1.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "WebApp"},
+		Object:    runtime.RawExtension{Raw: raw(spec)},
+	}}
+	if oldSpec != nil {
+		req.OldObject = runtime.RawExtension{Raw: raw(oldSpec)}
+	}
+	return req
+}
+
+func TestInstanceValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	rg := &v1alpha1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp", UID: types.UID("uid"), Generation: 1},
+		Spec: v1alpha1.ResourceGroupSpec{
+			Schema: &v1alpha1.Schema{Kind: "WebApp", APIVersion: "v1alpha1"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		objects   []*v1alpha1.ResourceGroup
+		req       func(t *testing.T) admission.Request
+		wantBuild bool
+	}{
+		{
+			name: "no resource group serves the kind",
+			req: func(t *testing.T) admission.Request {
+				return newInstanceRequest(t, admissionv1.Create, map[string]interface{}{"replicas": 1}, nil, false)
+			},
+		},
+		{
+			name:    "update without spec changes",
+			objects: []*v1alpha1.ResourceGroup{rg},
+			req: func(t *testing.T) admission.Request {
+				spec := map[string]interface{}{"replicas": 1}
+				return newInstanceRequest(t, admissionv1.Update, spec, spec, false)
+			},
+		},
+		{
+			name:    "instance being deleted",
+			objects: []*v1alpha1.ResourceGroup{rg},
+			req: func(t *testing.T) admission.Request {
+				return newInstanceRequest(t, admissionv1.Update, map[string]interface{}{"replicas": 2}, map[string]interface{}{"replicas": 1}, true)
+			},
+		},
+		{
+			name:    "invalid resource group",
+			objects: []*v1alpha1.ResourceGroup{rg},
+			req: func(t *testing.T) admission.Request {
+				return newInstanceRequest(t, admissionv1.Create, map[string]interface{}{"replicas": 1}, nil, false)
+			},
+			wantBuild: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, obj := range tt.objects {
+				builder = builder.WithObjects(obj.DeepCopy())
+			}
+			rgBuilder := &countingBuilder{err: errors.New("invalid resource group")}
+			v := &InstanceValidator{
+				client:  builder.Build(),
+				builder: rgBuilder,
+				graphs:  make(map[types.UID]cachedGraph),
+			}
+
+			resp := v.Handle(context.Background(), tt.req(t))
+			assert.True(t, resp.Allowed)
+			assert.Equal(t, tt.wantBuild, rgBuilder.calls > 0)
+		})
+	}
+}
+
+type countingBuilder struct {
+	err   error
+	calls int
+}
+
+func (b *countingBuilder) NewResourceGroup(_ context.Context, _ *v1alpha1.ResourceGroup) (*graph.Graph, error) {
+	b.calls++
+	return nil, b.err
+}
@@ -0,0 +1,202 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/graph"
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+//+kubebuilder:webhook:path=/validate-kro-run-v1alpha1-resourcegroup,mutating=false,failurePolicy=fail,sideEffects=None,groups=kro.run,resources=resourcegroups,verbs=create;update,versions=v1alpha1,name=vresourcegroup.kro.run,admissionReviewVersions=v1
+
+var resourceGroupGroupKind = v1alpha1.GroupVersion.WithKind("ResourceGroup").GroupKind()
+
+// resourceGroupBuilder builds the graph of a resource group.
+type resourceGroupBuilder interface {
+	NewResourceGroup(ctx context.Context, rg *v1alpha1.ResourceGroup) (*graph.Graph, error)
+}
+
+// ResourceGroupValidator validates resource groups on admission. It runs the
+// same graph build as the resource group controller, so that invalid resource
+// groups are rejected by the API server instead of being reported in their
+// status.
+type ResourceGroupValidator struct {
+	builder   resourceGroupBuilder
+	crdClient kroclient.CRDClient
+}
+
+var _ admission.CustomValidator = &ResourceGroupValidator{}
+
+// NewResourceGroupValidator creates a new ResourceGroupValidator.
+func NewResourceGroupValidator(builder *graph.Builder, crdClient kroclient.CRDClient) *ResourceGroupValidator {
+	return &ResourceGroupValidator{
+		builder:   builder,
+		crdClient: crdClient,
+	}
+}
+
+// SetupWithManager registers the validator with the webhook server of the
+// manager.
+func (v *ResourceGroupValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.ResourceGroup{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate builds the graph of the new resource group.
+func (v *ResourceGroupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rg, err := asResourceGroup(obj)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := v.buildGraph(ctx, rg); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ValidateUpdate builds the graph of the updated resource group, and checks
+// the changes it makes to the schema of the existing CRD. Breaking changes are
+// rejected, unless the resource group opts in through the allow breaking
+// changes annotation.
+func (v *ResourceGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	rg, err := asResourceGroup(newObj)
+	if err != nil {
+		return nil, err
+	}
+	oldRG, err := asResourceGroup(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	// Resource groups being deleted only get their finalizers removed.
+	if rg.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	// Updates that don't touch the spec, e.g the finalizers set by the
+	// resource group controller, don't need to be validated again.
+	if reflect.DeepEqual(oldRG.Spec, rg.Spec) {
+		return nil, nil
+	}
+
+	g, err := v.buildGraph(ctx, rg)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validateCRDChanges(ctx, rg, g.Instance.GetCRD())
+}
+
+// validateCRDChanges checks the changes made by the desired CRD of a resource
+// group to the schema of the live one, if any.
+func (v *ResourceGroupValidator) validateCRDChanges(
+	ctx context.Context,
+	rg *v1alpha1.ResourceGroup,
+	desired *extv1.CustomResourceDefinition,
+) (admission.Warnings, error) {
+	live, err := v.crdClient.Get(ctx, desired.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get CRD %s: %w", desired.Name, err)
+	}
+
+	breaking := kroclient.DiffCRDSchemas(live, desired).Breaking()
+	if len(breaking) == 0 {
+		return nil, nil
+	}
+	if metadata.AllowsBreakingChanges(rg.GetAnnotations()) {
+		return admission.Warnings{
+			fmt.Sprintf("breaking changes to CRD %s are allowed by the %s annotation: %s",
+				desired.Name, metadata.AllowBreakingChangesAnnotation, breaking),
+		}, nil
+	}
+	return nil, apierrors.NewInvalid(resourceGroupGroupKind, rg.Name, field.ErrorList{
+		field.Forbidden(field.NewPath("spec", "schema"), fmt.Sprintf(
+			"breaking changes to CRD %s: %s, set the %s annotation to \"true\" to allow them",
+			desired.Name, breaking, metadata.AllowBreakingChangesAnnotation,
+		)),
+	})
+}
+
+// ValidateDelete accepts all deletions.
+func (v *ResourceGroupValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// buildGraph builds the graph of a resource group. Validation errors are
+// returned as an Invalid API error listing every error found.
+func (v *ResourceGroupValidator) buildGraph(ctx context.Context, rg *v1alpha1.ResourceGroup) (*graph.Graph, error) {
+	g, err := v.builder.NewResourceGroup(ctx, rg)
+	if err == nil {
+		return g, nil
+	}
+
+	var validationErrs graph.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, fmt.Errorf("failed to build resource group: %w", err)
+	}
+	errs := make(field.ErrorList, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		errs = append(errs, toFieldError(validationErr))
+	}
+	return nil, apierrors.NewInvalid(resourceGroupGroupKind, rg.Name, errs)
+}
+
+// toFieldError converts a graph validation error to a field error. Errors
+// specific to a resource are reported under the resource, e.g
+// spec.resources[vpc].readyWhen[0].
+func toFieldError(err *graph.ValidationError) *field.Error {
+	var path *field.Path
+	switch {
+	case err.ResourceID != "":
+		path = field.NewPath("spec", "resources").Key(err.ResourceID)
+		if err.Path != "" {
+			path = path.Child(err.Path)
+		}
+	case err.Path != "":
+		path = field.NewPath(err.Path)
+	default:
+		path = field.NewPath("spec")
+	}
+
+	var value interface{} = field.OmitValueType{}
+	if err.Expression != "" {
+		value = err.Expression
+	}
+	return field.Invalid(path, value, err.Message)
+}
+
+func asResourceGroup(obj runtime.Object) (*v1alpha1.ResourceGroup, error) {
+	rg, ok := obj.(*v1alpha1.ResourceGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a ResourceGroup, got %T", obj)
+	}
+	return rg, nil
+}
@@ -0,0 +1,250 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/awslabs/kro/api/v1alpha1"
+	kroclient "github.com/awslabs/kro/pkg/client"
+	"github.com/awslabs/kro/pkg/graph"
+	"github.com/awslabs/kro/pkg/metadata"
+)
+
+type fakeBuilder struct {
+	err error
+}
+
+func (b *fakeBuilder) NewResourceGroup(_ context.Context, _ *v1alpha1.ResourceGroup) (*graph.Graph, error) {
+	return &graph.Graph{}, b.err
+}
+
+type fakeCRDClient struct {
+	kroclient.CRDClient
+	crds map[string]*extv1.CustomResourceDefinition
+}
+
+func (c *fakeCRDClient) Get(_ context.Context, name string) (*extv1.CustomResourceDefinition, error) {
+	crd, ok := c.crds[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, name)
+	}
+	return crd, nil
+}
+
+func newTestCRD(replicasType string) *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapps.kro.run"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Versions: []extv1.CustomResourceDefinitionVersion{{
+				Name: "v1alpha1",
+				Schema: &extv1.CustomResourceValidation{
+					OpenAPIV3Schema: &extv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]extv1.JSONSchemaProps{
+									"replicas": {Type: replicasType},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestResourceGroupValidator_ValidateCreate(t *testing.T) {
+	rg := &v1alpha1.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "webapp"}}
+
+	tests := []struct {
+		name        string
+		buildErr    error
+		wantInvalid bool
+		wantCauses  []metav1.StatusCause
+	}{
+		{
+			name: "valid resource group",
+		},
+		{
+			name: "validation errors",
+			buildErr: graph.ValidationErrors{
+				{ResourceID: "vpc", Path: "readyWhen[0]", Expression: "vpc.status.ready", Message: "expression must return a boolean"},
+				{ResourceID: "subnet", Message: "unknown kind"},
+				{Path: "spec.schema", Message: "invalid schema"},
+				{Message: "cycle detected"},
+			},
+			wantInvalid: true,
+			wantCauses: []metav1.StatusCause{
+				{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec.resources[vpc].readyWhen[0]", Message: "Invalid value: \"vpc.status.ready\": expression must return a boolean"},
+				{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec.resources[subnet]", Message: "Invalid value: unknown kind"},
+				{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec.schema", Message: "Invalid value: invalid schema"},
+				{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec", Message: "Invalid value: cycle detected"},
+			},
+		},
+		{
+			name:     "other errors",
+			buildErr: errors.New("discovery failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ResourceGroupValidator{builder: &fakeBuilder{err: tt.buildErr}}
+			_, err := v.ValidateCreate(context.Background(), rg)
+			if tt.buildErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantInvalid, apierrors.IsInvalid(err))
+			if tt.wantInvalid {
+				var statusErr *apierrors.StatusError
+				require.True(t, errors.As(err, &statusErr))
+				assert.Equal(t, tt.wantCauses, statusErr.Status().Details.Causes)
+			}
+		})
+	}
+}
+
+func TestResourceGroupValidator_ValidateUpdate(t *testing.T) {
+	newRG := func(kind string) *v1alpha1.ResourceGroup {
+		return &v1alpha1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "webapp", Generation: 1},
+			Spec: v1alpha1.ResourceGroupSpec{
+				Schema: &v1alpha1.Schema{Kind: kind, APIVersion: "v1alpha1"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		update    func(rg *v1alpha1.ResourceGroup)
+		wantBuild bool
+	}{
+		{
+			name: "finalizer added",
+			update: func(rg *v1alpha1.ResourceGroup) {
+				rg.Finalizers = []string{"kro.run/finalizer"}
+			},
+		},
+		{
+			name: "annotation added",
+			update: func(rg *v1alpha1.ResourceGroup) {
+				rg.Annotations = map[string]string{metadata.AllowBreakingChangesAnnotation: "true"}
+			},
+		},
+		{
+			name: "resource group being deleted",
+			update: func(rg *v1alpha1.ResourceGroup) {
+				now := metav1.Now()
+				rg.DeletionTimestamp = &now
+				rg.Spec.Schema.Kind = "WebService"
+			},
+		},
+		{
+			name: "spec changed",
+			update: func(rg *v1alpha1.ResourceGroup) {
+				rg.Generation++
+				rg.Spec.Schema.Kind = "WebService"
+			},
+			wantBuild: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := &countingBuilder{err: errors.New("invalid resource group")}
+			v := &ResourceGroupValidator{builder: builder}
+
+			oldRG := newRG("WebApp")
+			rg := oldRG.DeepCopy()
+			tt.update(rg)
+
+			_, err := v.ValidateUpdate(context.Background(), oldRG, rg)
+			assert.Equal(t, tt.wantBuild, builder.calls > 0)
+			if tt.wantBuild {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestResourceGroupValidator_ValidateCRDChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		live         *extv1.CustomResourceDefinition
+		desired      *extv1.CustomResourceDefinition
+		annotations  map[string]string
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:    "no live CRD",
+			desired: newTestCRD("string"),
+		},
+		{
+			name:    "no breaking changes",
+			live:    newTestCRD("integer"),
+			desired: newTestCRD("integer"),
+		},
+		{
+			name:    "breaking changes",
+			live:    newTestCRD("integer"),
+			desired: newTestCRD("string"),
+			wantErr: true,
+		},
+		{
+			name:         "breaking changes allowed",
+			live:         newTestCRD("integer"),
+			desired:      newTestCRD("string"),
+			annotations:  map[string]string{metadata.AllowBreakingChangesAnnotation: "true"},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crdClient := &fakeCRDClient{crds: map[string]*extv1.CustomResourceDefinition{}}
+			if tt.live != nil {
+				crdClient.crds[tt.live.Name] = tt.live
+			}
+			v := &ResourceGroupValidator{crdClient: crdClient}
+			rg := &v1alpha1.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "webapp", Annotations: tt.annotations}}
+
+			warnings, err := v.validateCRDChanges(context.Background(), rg, tt.desired)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), "spec.replicas: TypeChanged (integer -> string)")
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, warnings, tt.wantWarnings)
+		})
+	}
+}
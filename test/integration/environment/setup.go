@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	krocel "github.com/awslabs/kro/pkg/cel"
 	kroclient "github.com/awslabs/kro/pkg/client"
 	ctrlinstance "github.com/awslabs/kro/pkg/controller/instance"
 	ctrlresourcegroup "github.com/awslabs/kro/pkg/controller/resourcegroup"
@@ -124,7 +125,7 @@ func (e *Environment) initializeClients() error {
 	})
 
 	restConfig := e.ClientSet.RESTConfig()
-	e.GraphBuilder, err = graph.NewBuilder(restConfig)
+	e.GraphBuilder, err = graph.NewBuilder(restConfig, krocel.DefaultLimits())
 	if err != nil {
 		return fmt.Errorf("creating graph builder: %w", err)
 	}
@@ -150,15 +151,6 @@ func (e *Environment) setupController() error {
 		}
 	}()
 
-	rgReconciler := ctrlresourcegroup.NewResourceGroupReconciler(
-		noopLogger(),
-		e.Client,
-		e.ClientSet,
-		e.ControllerConfig.AllowCRDDeletion,
-		dc,
-		e.GraphBuilder,
-	)
-
 	var err error
 	e.CtrlManager, err = ctrl.NewManager(e.ClientSet.RESTConfig(), ctrl.Options{
 		Scheme: scheme.Scheme,
@@ -171,6 +163,16 @@ func (e *Environment) setupController() error {
 		return fmt.Errorf("creating manager: %w", err)
 	}
 
+	rgReconciler := ctrlresourcegroup.NewResourceGroupReconciler(
+		noopLogger(),
+		e.Client,
+		e.ClientSet,
+		e.ControllerConfig.AllowCRDDeletion,
+		dc,
+		e.GraphBuilder,
+		e.CtrlManager.GetEventRecorderFor("kro"),
+	)
+
 	if err = rgReconciler.SetupWithManager(e.CtrlManager); err != nil {
 		return fmt.Errorf("setting up reconciler: %w", err)
 	}
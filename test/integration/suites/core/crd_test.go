@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
+	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/metadata"
 	"github.com/awslabs/kro/pkg/testutil/generator"
 )
 
@@ -94,6 +96,13 @@ var _ = Describe("CRD", func() {
 				g.Expect(props["spec"].Properties["field1"].Type).To(Equal("string"))
 				g.Expect(props["spec"].Properties["field2"].Type).To(Equal("integer"))
 				g.Expect(props["spec"].Properties["field2"].Default.Raw).To(Equal([]byte("42")))
+
+				// Verify the CRD can be traced back to the ResourceGroup
+				g.Expect(crd.Labels).To(HaveKeyWithValue(metadata.OwnedLabel, "true"))
+				g.Expect(crd.Labels).To(HaveKeyWithValue(metadata.ResourceGroupNameLabel, rg.Name))
+				g.Expect(crd.Labels).To(HaveKeyWithValue(metadata.ResourceGroupNamespaceLabel, namespace))
+				g.Expect(crd.Annotations).To(HaveKeyWithValue(metadata.ResourceGroupOwnerAnnotation, namespace+"/"+rg.Name))
+				g.Expect(crd.OwnerReferences).To(BeEmpty())
 			}, 10*time.Second, time.Second).Should(Succeed())
 		})
 
@@ -183,4 +192,102 @@ var _ = Describe("CRD", func() {
 			}, 10*time.Second, time.Second).Should(BeTrue())
 		})
 	})
+
+	Context("Kind conflicts", func() {
+		It("should put the second ResourceGroup claiming a kind in conflict", func() {
+			newRG := func(name string) *krov1alpha1.ResourceGroup {
+				return generator.NewResourceGroup(name,
+					generator.WithNamespace(namespace),
+					generator.WithSchema(
+						"TestConflict", "v1alpha1",
+						map[string]interface{}{
+							"field1": "string",
+						},
+						nil,
+					),
+				)
+			}
+
+			owner := newRG("test-conflict-owner")
+			Expect(env.Client.Create(ctx, owner)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{
+					Name:      owner.Name,
+					Namespace: namespace,
+				}, owner)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(owner.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			claimant := newRG("test-conflict-claimant")
+			Expect(env.Client.Create(ctx, claimant)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{
+					Name:      claimant.Name,
+					Namespace: namespace,
+				}, claimant)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(claimant.Status.State).To(Equal(krov1alpha1.ResourceGroupStateConflict))
+
+				var kindClaimed *krov1alpha1.Condition
+				for i := range claimant.Status.Conditions {
+					if claimant.Status.Conditions[i].Type == krov1alpha1.ResourceGroupConditionTypeKindClaimed {
+						kindClaimed = &claimant.Status.Conditions[i]
+					}
+				}
+				g.Expect(kindClaimed).ToNot(BeNil())
+				g.Expect(kindClaimed.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(*kindClaimed.Reason).To(ContainSubstring(namespace + "/" + owner.Name))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			// Deleting the claimant leaves the CRD of the owner in place.
+			Expect(env.Client.Delete(ctx, claimant)).To(Succeed())
+			Eventually(func() bool {
+				err := env.Client.Get(ctx, types.NamespacedName{
+					Name:      claimant.Name,
+					Namespace: namespace,
+				}, claimant)
+				return errors.IsNotFound(err)
+			}, 10*time.Second, time.Second).Should(BeTrue())
+
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			Expect(env.Client.Get(ctx, types.NamespacedName{Name: "testconflicts.kro.run"}, crd)).To(Succeed())
+			Expect(crd.Annotations[metadata.ResourceGroupOwnerAnnotation]).To(Equal(namespace + "/" + owner.Name))
+
+			Expect(env.Client.Get(ctx, types.NamespacedName{
+				Name:      owner.Name,
+				Namespace: namespace,
+			}, owner)).To(Succeed())
+			Expect(owner.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+
+			// Once the owner is deleted, a ResourceGroup in conflict claims
+			// the kind on its next attempt.
+			claimant = newRG("test-conflict-claimant-2")
+			Expect(env.Client.Create(ctx, claimant)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{
+					Name:      claimant.Name,
+					Namespace: namespace,
+				}, claimant)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(claimant.Status.State).To(Equal(krov1alpha1.ResourceGroupStateConflict))
+			}, 10*time.Second, time.Second).Should(Succeed())
+
+			Expect(env.Client.Delete(ctx, owner)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := env.Client.Get(ctx, types.NamespacedName{
+					Name:      claimant.Name,
+					Namespace: namespace,
+				}, claimant)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(claimant.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+
+				crd := &apiextensionsv1.CustomResourceDefinition{}
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: "testconflicts.kro.run"}, crd)).To(Succeed())
+				g.Expect(crd.Annotations[metadata.ResourceGroupOwnerAnnotation]).To(Equal(namespace + "/" + claimant.Name))
+			}, 60*time.Second, time.Second).Should(Succeed())
+
+			Expect(env.Client.Delete(ctx, claimant)).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,147 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/metadata"
+	"github.com/awslabs/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Deletion", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		// Create namespace
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	createResourceGroupWithInstance := func(name, kind string) (*krov1alpha1.ResourceGroup, *unstructured.Unstructured) {
+		rg := generator.NewResourceGroup(name,
+			generator.WithNamespace(namespace),
+			generator.WithSchema(
+				kind, "v1alpha1",
+				map[string]interface{}{
+					"field1": "string",
+				},
+				nil,
+			),
+		)
+		Expect(env.Client.Create(ctx, rg)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rg.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KroDomainName, "v1alpha1"),
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"field1": "value",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+		return rg, instance
+	}
+
+	It("should hold the ResourceGroup deletion while instances exist", func() {
+		rg, instance := createResourceGroupWithInstance("test-deletion-blocked", "TestDeletionBlocked")
+
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+
+		// Verify the deletion is held, and the remaining instances reported
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			condition := krov1alpha1.GetCondition(rg.Status.Conditions,
+				krov1alpha1.ResourceGroupConditionTypeInstancesCleanedUp)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(*condition.Reason).To(ContainSubstring("1 instances of TestDeletionBlocked still exist"))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		// Delete the instance
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+
+		// Verify the ResourceGroup is deleted once the instance is gone
+		Eventually(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, &krov1alpha1.ResourceGroup{})
+			return errors.IsNotFound(err)
+		}, 60*time.Second, time.Second).Should(BeTrue())
+	})
+
+	It("should delete the ResourceGroup with remaining instances when forced", func() {
+		rg, _ := createResourceGroupWithInstance("test-deletion-forced", "TestDeletionForced")
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			rg.SetAnnotations(map[string]string{metadata.ForceDeletionAnnotation: "true"})
+			g.Expect(env.Client.Update(ctx, rg)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+
+		Eventually(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, &krov1alpha1.ResourceGroup{})
+			return errors.IsNotFound(err)
+		}, 10*time.Second, time.Second).Should(BeTrue())
+	})
+})
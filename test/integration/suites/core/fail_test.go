@@ -0,0 +1,242 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Failures", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		// Create namespace
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	syncedCondition := func(instance *unstructured.Unstructured) map[string]interface{} {
+		conditions, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "InstanceSynced" {
+				return condition
+			}
+		}
+		return nil
+	}
+
+	It("should fail instances with a resource matching its failWhen expressions", func() {
+		rg := generator.NewResourceGroup("test-fail",
+			generator.WithNamespace(namespace),
+			generator.WithSchema(
+				"TestFail", "v1alpha1",
+				map[string]interface{}{
+					"name":  "string",
+					"state": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"state": "${schema.spec.state}",
+				},
+			}, nil, nil),
+			generator.WithResourceFields("configmap", func(r *krov1alpha1.Resource) {
+				r.FailWhen = []string{`${configmap.data.state == "failed"}`}
+			}),
+		)
+		Expect(env.Client.Create(ctx, rg)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rg.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-fail"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KroDomainName, "v1alpha1"),
+				"kind":       "TestFail",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name":  name,
+					"state": "failed",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The instance reports the expression and the value it observed
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(instance.Object["status"].(map[string]interface{})["state"]).To(Equal("ERROR"))
+			condition := syncedCondition(instance)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition["status"]).To(Equal("False"))
+			g.Expect(condition["reason"]).To(Equal("ResourceFailed"))
+			g.Expect(condition["message"]).To(ContainSubstring(`configmap.data.state == "failed"`))
+			g.Expect(condition["message"]).To(ContainSubstring(`configmap.data.state="failed"`))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// The instance isn't reconciled again until its spec changes, its
+		// status would be patched otherwise
+		resourceVersion := instance.GetResourceVersion()
+		Consistently(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(instance.GetResourceVersion()).To(Equal(resourceVersion))
+		}, 5*time.Second, time.Second).Should(Succeed())
+
+		// Cleanup
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+	})
+
+	It("should fail instances with a resource not ready before its ready timeout", func() {
+		rg := generator.NewResourceGroup("test-ready-timeout",
+			generator.WithNamespace(namespace),
+			generator.WithSchema(
+				"TestReadyTimeout", "v1alpha1",
+				map[string]interface{}{
+					"name":  "string",
+					"state": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"state": "${schema.spec.state}",
+				},
+			}, []string{`${configmap.data.state == "ready"}`}, nil),
+			generator.WithResourceFields("configmap", func(r *krov1alpha1.Resource) {
+				r.ReadinessPolicy = &krov1alpha1.ReadinessPolicy{
+					InitialDelay: &metav1.Duration{Duration: time.Second},
+				}
+				r.ReadyTimeout = &metav1.Duration{Duration: 3 * time.Second}
+			}),
+		)
+		Expect(env.Client.Create(ctx, rg)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rg.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-ready-timeout"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KroDomainName, "v1alpha1"),
+				"kind":       "TestReadyTimeout",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name":  name,
+					"state": "pending",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The instance waits for the resource first
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(instance.Object["status"].(map[string]interface{})["state"]).To(Equal("IN_PROGRESS"))
+		}, 10*time.Second, 500*time.Millisecond).Should(Succeed())
+
+		// Then fails once the resource isn't ready after 3 seconds
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(instance.Object["status"].(map[string]interface{})["state"]).To(Equal("ERROR"))
+			condition := syncedCondition(instance)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition["reason"]).To(Equal("ResourceFailed"))
+			g.Expect(condition["message"]).To(ContainSubstring("resource configmap failed: not ready after 3s"))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// The instance isn't reconciled again until its spec changes
+		resourceVersion := instance.GetResourceVersion()
+		Consistently(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(instance.GetResourceVersion()).To(Equal(resourceVersion))
+		}, 5*time.Second, time.Second).Should(Succeed())
+
+		// Cleanup
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+	})
+})
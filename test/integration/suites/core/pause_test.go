@@ -0,0 +1,276 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/awslabs/kro/api/v1alpha1"
+	"github.com/awslabs/kro/pkg/metadata"
+	"github.com/awslabs/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Pause", func() {
+	var (
+		ctx       context.Context
+		namespace string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		// Create namespace
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		})).To(Succeed())
+	})
+
+	pausedCondition := func(instance *unstructured.Unstructured) map[string]interface{} {
+		conditions, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == string(krov1alpha1.InstanceConditionTypePaused) {
+				return condition
+			}
+		}
+		return nil
+	}
+
+	It("should not reconcile paused instances until they are resumed", func() {
+		rg := generator.NewResourceGroup("test-pause",
+			generator.WithNamespace(namespace),
+			generator.WithSchema(
+				"TestPause", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rg)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rg.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-pause"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KroDomainName, "v1alpha1"),
+				"kind":       "TestPause",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+					"annotations": map[string]interface{}{
+						metadata.PausedAnnotation: "true",
+					},
+				},
+				"spec": map[string]interface{}{
+					"name": name,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The paused instance only gets the Paused condition
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			condition := pausedCondition(instance)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition["status"]).To(Equal("True"))
+			g.Expect(condition["reason"]).To(Equal("InstancePaused"))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Consistently(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			return errors.IsNotFound(err)
+		}, 3*time.Second, time.Second).Should(BeTrue())
+
+		// Resume the instance
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			instance.SetAnnotations(nil)
+			g.Expect(env.Client.Update(ctx, instance)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pausedCondition(instance)).To(BeNil())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// Cleanup
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+	})
+
+	It("should not reconcile the instances of a paused ResourceGroup until it is resumed", func() {
+		rg := generator.NewResourceGroup("test-pause-rg",
+			generator.WithNamespace(namespace),
+			generator.WithSchema(
+				"TestPauseRG", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"key": "value",
+				},
+			}, nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rg)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rg.Status.State).To(Equal(krov1alpha1.ResourceGroupStateActive))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		// Pausing the ResourceGroup doesn't change its generation
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			rg.SetAnnotations(map[string]string{metadata.PausedAnnotation: "true"})
+			g.Expect(env.Client.Update(ctx, rg)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		name := "test-pause-rg"
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KroDomainName, "v1alpha1"),
+				"kind":       "TestPauseRG",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name": name,
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		// The instance only gets the Paused condition
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			condition := pausedCondition(instance)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition["status"]).To(Equal("True"))
+			g.Expect(condition["reason"]).To(Equal("ResourceGroupPaused"))
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Consistently(func() bool {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			return errors.IsNotFound(err)
+		}, 3*time.Second, time.Second).Should(BeTrue())
+
+		// Resume the ResourceGroup
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      rg.Name,
+				Namespace: namespace,
+			}, rg)
+			g.Expect(err).ToNot(HaveOccurred())
+			rg.SetAnnotations(nil)
+			g.Expect(env.Client.Update(ctx, rg)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &corev1.ConfigMap{})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = env.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, instance)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pausedCondition(instance)).To(BeNil())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		// Cleanup
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+		Expect(env.Client.Delete(ctx, rg)).To(Succeed())
+	})
+})